@@ -0,0 +1,90 @@
+package pokecache
+
+import (
+	"sync"
+	"time"
+)
+
+// aliasPrefix is prepended to the keys persistent aliases are stored under,
+// so they cannot collide with the values they point to.
+const aliasPrefix = "alias:"
+
+// Aliases maps alternative keys for the same value, such as the name and ID
+// URLs of a PokeAPI resource, to the key the value is cached under.
+type Aliases struct {
+	mu sync.RWMutex
+	// m also remembers aliases that were looked up in the cache and not
+	// found, as mapping to themselves, so the cache is asked only once.
+	m map[string]string
+
+	// cache and ttl are only set for persistent aliases.
+	cache Cache
+	ttl   time.Duration
+}
+
+func NewAliases() *Aliases {
+	return &Aliases{m: make(map[string]string)}
+}
+
+// NewPersistentAliases returns Aliases that are also stored in cache, where
+// they expire after ttl, so they survive a restart if cache has a disk tier.
+// Reading them back does not count as a lookup in the cache's Stats.
+func NewPersistentAliases(cache Cache, ttl time.Duration) *Aliases {
+	return &Aliases{m: make(map[string]string), cache: cache, ttl: ttl}
+}
+
+// Add records that alias refers to the value cached under key.
+func (a *Aliases) Add(alias, key string) {
+	if alias == key {
+		return
+	}
+	a.mu.Lock()
+	known := a.m[alias] == key
+	a.m[alias] = key
+	a.mu.Unlock()
+	if a.cache != nil && !known {
+		a.cache.AddWithTTL(aliasPrefix+alias, []byte(key), a.ttl)
+	}
+}
+
+// Resolve returns the key cached for alias, or alias itself when it is not
+// known.
+func (a *Aliases) Resolve(alias string) string {
+	a.mu.RLock()
+	key, ok := a.m[alias]
+	a.mu.RUnlock()
+	if ok {
+		return key
+	}
+	if a.cache == nil {
+		return alias
+	}
+
+	key = alias
+	if stored, ok := lookupStored(a.cache, aliasPrefix+alias); ok {
+		key = string(stored)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// An Add that raced with the lookup wins.
+	if added, ok := a.m[alias]; ok {
+		return added
+	}
+	a.m[alias] = key
+	return key
+}
+
+// lookupStored reads key from c without counting a hit or a miss, falling
+// back to Get for caches outside this package.
+func lookupStored(c Cache, key string) ([]byte, bool) {
+	s, ok := c.(entryStore)
+	if !ok {
+		return c.Get(key)
+	}
+	entry, exists := s.lookup(key)
+	if !exists || entry.negative {
+		return nil, false
+	}
+	val, err := decodeChecked(entry)
+	return val, err == nil
+}
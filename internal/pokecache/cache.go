@@ -0,0 +1,140 @@
+package pokecache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache is implemented by every cache backend. MemoryCache keeps entries in a
+// bounded map, DirCache stores one file per key below a directory.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Add(key string, val []byte)
+	AddWithTTL(key string, val []byte, ttl time.Duration)
+	GetOrFetch(ctx context.Context, key string, ttl time.Duration, fetch FetchFunc) ([]byte, error)
+	Delete(key string)
+	Keys() []string
+	Stats() Stats
+	Close() error
+}
+
+// PurgePrefix removes every key starting with prefix and reports how many
+// keys were removed.
+func PurgePrefix(c Cache, prefix string) int {
+	purged := 0
+	for _, key := range c.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			c.Delete(key)
+			purged++
+		}
+	}
+	return purged
+}
+
+// Clear removes every entry from c.
+func Clear(c Cache) {
+	PurgePrefix(c, "")
+}
+
+type options struct {
+	clock           Clock
+	codec           Codec
+	minCompressSize int
+
+	// Expired entries are kept for staleRetention so they can be revalidated
+	// instead of downloaded again. Within staleWhileRevalidate they are even
+	// served while the revalidation runs in the background.
+	staleRetention       time.Duration
+	staleWhileRevalidate time.Duration
+	negativeTTL          time.Duration
+
+	onCorrupt func(err *CorruptError)
+	onError   func(err error)
+
+	// Only used by MemoryCache.
+	maxEntries int
+	maxBytes   int
+	shards     int
+	disk       *diskStore
+}
+
+// Option configures optional behaviour of a Cache. Options that do not apply
+// to a backend are ignored by it.
+type Option func(*options)
+
+// core holds the configuration, statistics and in-flight fetches that every
+// backend shares.
+type core struct {
+	options
+	counters counters
+
+	flightMu sync.Mutex
+	inflight map[string]*call
+}
+
+func (c *core) init(opts []Option) {
+	c.clock = realClock{}
+	c.codec = Gzip
+	c.minCompressSize = defaultMinCompressSize
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+	if c.disk != nil {
+		c.disk.now = c.clock.Now
+	}
+	c.inflight = make(map[string]*call)
+}
+
+// WithErrorHandler calls fn for every disk error the cache cannot return to
+// its caller, such as a failed write from Add. Without a handler these errors
+// are only counted in Stats.
+func WithErrorHandler(fn func(err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+func (c *core) reportError(err error) {
+	c.counters.errors.Add(1)
+	if c.onError != nil {
+		c.onError(err)
+	}
+}
+
+func (c *core) now() time.Time {
+	return c.clock.Now()
+}
+
+// newEntry encodes val into an entry that expires after ttl.
+func (c *core) newEntry(val []byte, ttl time.Duration, validators Validators) (cacheEntry, error) {
+	encoded, err := encode(c.codec, c.minCompressSize, val)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	createdAt := c.now()
+	return cacheEntry{
+		createdAt: createdAt,
+		expiresAt: createdAt.Add(ttl),
+		val:       encoded,
+		rawSize:   len(val),
+		checksum:  checksum(encoded),
+
+		validators: validators,
+	}, nil
+}
+
+// countLookup records a plain Get as a hit or a miss.
+func (c *core) countLookup(hit bool) {
+	if hit {
+		c.counters.hits.Add(1)
+	} else {
+		c.counters.misses.Add(1)
+	}
+}
+
+var (
+	_ Cache = (*MemoryCache)(nil)
+	_ Cache = (*DirCache)(nil)
+)
@@ -0,0 +1,173 @@
+package pokecache
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Codec compresses cache values. Every stored value starts with the ID of the
+// codec that encoded it, so entries written with different codecs can live
+// side by side, in memory as well as on disk.
+type Codec interface {
+	ID() byte
+	Name() string
+	Encode(data []byte) ([]byte, error)
+	Decode(data []byte) ([]byte, error)
+}
+
+var (
+	None  Codec = noneCodec{}
+	Gzip  Codec = gzipCodec{}
+	Zlib  Codec = zlibCodec{}
+	Flate Codec = flateCodec{}
+)
+
+var codecs = map[byte]Codec{
+	None.ID():  None,
+	Gzip.ID():  Gzip,
+	Zlib.ID():  Zlib,
+	Flate.ID(): Flate,
+}
+
+// Values smaller than this are stored raw by default, since the compression
+// header would cost more than it saves.
+const defaultMinCompressSize = 256
+
+// WithCodec selects the codec used for new entries. Entries encoded with
+// another codec can still be read.
+func WithCodec(codec Codec) Option {
+	return func(o *options) {
+		o.codec = codec
+	}
+}
+
+// WithMinCompressSize stores values shorter than n bytes raw instead of
+// running them through the codec.
+func WithMinCompressSize(n int) Option {
+	return func(o *options) {
+		o.minCompressSize = n
+	}
+}
+
+// encode prefixes the encoded value with the ID of the codec that was used.
+func encode(codec Codec, minSize int, data []byte) ([]byte, error) {
+	if len(data) < minSize {
+		codec = None
+	}
+	encoded, err := codec.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codec.ID()}, encoded...), nil
+}
+
+// decode picks the codec from the marker byte written by encode.
+func decode(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("pokecache: empty entry")
+	}
+	codec, ok := codecs[data[0]]
+	if !ok {
+		return nil, fmt.Errorf("pokecache: unknown codec %d", data[0])
+	}
+	return codec.Decode(data[1:])
+}
+
+type noneCodec struct{}
+
+func (noneCodec) ID() byte     { return 0 }
+func (noneCodec) Name() string { return "none" }
+
+func (noneCodec) Encode(data []byte) ([]byte, error) {
+	return bytes.Clone(data), nil
+}
+
+func (noneCodec) Decode(data []byte) ([]byte, error) {
+	return bytes.Clone(data), nil
+}
+
+type gzipCodec struct{}
+
+func (gzipCodec) ID() byte                           { return 1 }
+func (gzipCodec) Name() string                       { return "gzip" }
+func (gzipCodec) Encode(data []byte) ([]byte, error) { return compress(data) }
+func (gzipCodec) Decode(data []byte) ([]byte, error) { return decompress(data) }
+
+type zlibCodec struct{}
+
+func (zlibCodec) ID() byte     { return 2 }
+func (zlibCodec) Name() string { return "zlib" }
+
+func (zlibCodec) Encode(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (zlibCodec) Decode(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+type flateCodec struct{}
+
+func (flateCodec) ID() byte     { return 3 }
+func (flateCodec) Name() string { return "flate" }
+
+func (flateCodec) Encode(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (flateCodec) Decode(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Compress the data
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decompress the data
+func decompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,117 @@
+package pokecache
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// DirCache stores every entry in its own file below a directory. It keeps no
+// entries in memory, so its contents survive restarts and can be shared by
+// several processes.
+type DirCache struct {
+	core
+
+	disk *diskStore
+	ttl  time.Duration
+}
+
+// NewDirCache stores entries below dir for ttl unless AddWithTTL or
+// GetOrFetch ask for a different lifetime.
+func NewDirCache(dir string, ttl time.Duration, opts ...Option) *DirCache {
+	cache := &DirCache{ttl: ttl}
+	cache.init(opts)
+	cache.disk = &diskStore{dir: dir, retention: cache.staleRetention, now: cache.clock.Now}
+	return cache
+}
+
+func (c *DirCache) Add(key string, val []byte) {
+	c.AddWithTTL(key, val, c.ttl)
+}
+
+func (c *DirCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	entry, err := c.newEntry(val, ttl, Validators{})
+	if err != nil {
+		c.reportError(err)
+		return
+	}
+	c.store(key, entry)
+}
+
+func (c *DirCache) Get(key string) ([]byte, bool) {
+	entry, exists := c.lookup(key)
+	if exists && (entry.negative || !c.now().Before(entry.expiresAt)) {
+		exists = false
+	}
+	c.countLookup(exists)
+	if !exists {
+		return nil, false
+	}
+	val, err := c.decode(c, key, entry)
+	return val, err == nil
+}
+
+// GetOrFetch returns the cached value for key, calling fetch on a miss.
+func (c *DirCache) GetOrFetch(ctx context.Context, key string, ttl time.Duration, fetch FetchFunc) ([]byte, error) {
+	return c.getOrFetch(ctx, c, key, ttl, fetch)
+}
+
+func (c *DirCache) Delete(key string) {
+	if err := c.disk.remove(key); err != nil {
+		c.reportError(err)
+	}
+}
+
+// Keys returns the sorted keys of all entries on disk.
+func (c *DirCache) Keys() []string {
+	keys, err := c.disk.keys()
+	if err != nil {
+		c.reportError(err)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (c *DirCache) Stats() Stats {
+	stats := c.counters.snapshot()
+	err := c.disk.scan(func(key string, entry cacheEntry) {
+		stats.Entries++
+		stats.CompressedBytes += len(entry.val)
+		stats.UncompressedBytes += entry.rawSize
+	})
+	if err != nil {
+		c.reportError(err)
+	}
+	return stats
+}
+
+// Close is a no-op, a DirCache runs no background work.
+func (c *DirCache) Close() error {
+	return nil
+}
+
+func (c *DirCache) lookup(key string) (cacheEntry, bool) {
+	entry, exists, err := c.disk.read(key)
+	if err != nil {
+		c.readError(err)
+		return cacheEntry{}, false
+	}
+	return entry, exists
+}
+
+func (c *DirCache) store(key string, entry cacheEntry) {
+	if err := c.disk.write(key, entry); err != nil {
+		c.reportError(err)
+	}
+}
+
+func (c *DirCache) verify(report func(err *CorruptError)) error {
+	return c.disk.verify(func(err *CorruptError) {
+		c.counters.corruptions.Add(1)
+		report(err)
+	})
+}
+
+func (c *DirCache) scan(fn func(key string, entry cacheEntry)) error {
+	return c.disk.scan(fn)
+}
@@ -0,0 +1,255 @@
+package pokecache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// diskVersion is bumped whenever the on-disk entry layout changes so that
+// files written by older builds are treated as misses instead of garbage.
+const diskVersion byte = 7
+
+const diskFlagNegative byte = 1 << 0
+
+// errDiskVersion is returned for files written by a build with a different
+// layout. They are not corrupt, just unreadable, and are dropped silently.
+var errDiskVersion = errors.New("pokecache: unsupported disk entry version")
+
+var errTruncated = errors.New("truncated disk entry")
+
+// diskStore keeps one file per key below dir. As the disk tier of a
+// MemoryCache, entries outlive the process and expire after ttl, independent
+// of the in-memory reap interval. With a zero ttl, as used by DirCache, an
+// entry lives until its own expiry plus retention instead.
+type diskStore struct {
+	dir       string
+	ttl       time.Duration
+	retention time.Duration
+	now       func() time.Time
+}
+
+func newDiskStore(dir string, ttl time.Duration) *diskStore {
+	return &diskStore{dir: dir, ttl: ttl, now: time.Now}
+}
+
+func (d *diskStore) expired(entry cacheEntry) bool {
+	if d.ttl > 0 {
+		return d.now().Sub(entry.createdAt) >= d.ttl
+	}
+	return !d.now().Before(entry.expiresAt.Add(d.retention))
+}
+
+func (d *diskStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
+
+// write stores an already encoded entry. The file is written to a
+// temporary name first so a crash never leaves a half written entry behind.
+func (d *diskStore) write(key string, entry cacheEntry) error {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return err
+	}
+	data := encodeDiskEntry(key, entry)
+
+	tmp, err := os.CreateTemp(d.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path(key))
+}
+
+// read returns the encoded entry for key. Expired entries and entries written
+// with another layout are removed and reported as a miss, damaged files are
+// removed and reported as a *CorruptError.
+func (d *diskStore) read(key string) (cacheEntry, bool, error) {
+	path := d.path(key)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cacheEntry{}, false, nil
+	}
+	if err != nil {
+		return cacheEntry{}, false, err
+	}
+	storedKey, entry, err := decodeDiskEntry(data)
+	if errors.Is(err, errDiskVersion) {
+		os.Remove(path)
+		return cacheEntry{}, false, nil
+	}
+	if err != nil {
+		os.Remove(path)
+		return cacheEntry{}, false, &CorruptError{Key: key, Path: path, Err: err}
+	}
+	if storedKey != key {
+		return cacheEntry{}, false, nil
+	}
+	if d.expired(entry) {
+		os.Remove(path)
+		return cacheEntry{}, false, nil
+	}
+	return entry, true, nil
+}
+
+// encodeDiskEntry lays an entry out as a version byte, a fixed size header
+// including the value's checksum and the length prefixed key and validators,
+// followed by the value.
+func encodeDiskEntry(key string, entry cacheEntry) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(diskVersion)
+	binary.Write(&buf, binary.BigEndian, entry.createdAt.UnixNano())
+	binary.Write(&buf, binary.BigEndian, entry.expiresAt.UnixNano())
+	binary.Write(&buf, binary.BigEndian, uint32(entry.rawSize))
+	var flags byte
+	if entry.negative {
+		flags |= diskFlagNegative
+	}
+	buf.WriteByte(flags)
+	binary.Write(&buf, binary.BigEndian, entry.checksum)
+	for _, field := range []string{key, entry.validators.ETag, entry.validators.LastModified} {
+		binary.Write(&buf, binary.BigEndian, uint32(len(field)))
+		buf.WriteString(field)
+	}
+	buf.Write(entry.val)
+	return buf.Bytes()
+}
+
+func decodeDiskEntry(data []byte) (string, cacheEntry, error) {
+	const header = 1 + 8 + 8 + 4 + 1 + 4
+	if len(data) > 0 && data[0] != diskVersion {
+		return "", cacheEntry{}, errDiskVersion
+	}
+	if len(data) < header {
+		return "", cacheEntry{}, errTruncated
+	}
+	createdAt := int64(binary.BigEndian.Uint64(data[1:9]))
+	expiresAt := int64(binary.BigEndian.Uint64(data[9:17]))
+	rawSize := int(binary.BigEndian.Uint32(data[17:21]))
+	flags := data[21]
+	sum := binary.BigEndian.Uint32(data[22:26])
+	rest := data[header:]
+
+	var fields [3]string
+	for i := range fields {
+		if len(rest) < 4 {
+			return "", cacheEntry{}, errTruncated
+		}
+		n := int(binary.BigEndian.Uint32(rest))
+		rest = rest[4:]
+		if len(rest) < n {
+			return "", cacheEntry{}, errTruncated
+		}
+		fields[i] = string(rest[:n])
+		rest = rest[n:]
+	}
+	entry := cacheEntry{
+		createdAt: time.Unix(0, createdAt),
+		expiresAt: time.Unix(0, expiresAt),
+		val:       rest,
+		rawSize:   rawSize,
+		checksum:  sum,
+		validators: Validators{
+			ETag:         fields[1],
+			LastModified: fields[2],
+		},
+		negative: flags&diskFlagNegative != 0,
+	}
+	return fields[0], entry, nil
+}
+
+// scan calls fn for every unexpired entry on disk.
+func (d *diskStore) scan(fn func(key string, entry cacheEntry)) error {
+	files, err := os.ReadDir(d.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(d.dir, file.Name()))
+		if err != nil {
+			continue
+		}
+		key, entry, err := decodeDiskEntry(data)
+		if err != nil || d.expired(entry) {
+			continue
+		}
+		fn(key, entry)
+	}
+	return nil
+}
+
+// verify checks every unexpired file below dir. Damaged files are removed
+// and passed to report. Files with another layout are removed silently.
+func (d *diskStore) verify(report func(err *CorruptError)) error {
+	files, err := os.ReadDir(d.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(d.dir, file.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		key, entry, err := decodeDiskEntry(data)
+		if errors.Is(err, errDiskVersion) {
+			os.Remove(path)
+			continue
+		}
+		if err == nil {
+			if d.expired(entry) {
+				continue
+			}
+			_, err = decodeChecked(entry)
+		}
+		if err != nil {
+			os.Remove(path)
+			report(&CorruptError{Key: key, Path: path, Err: err})
+		}
+	}
+	return nil
+}
+
+// keys lists the keys of all unexpired entries on disk.
+func (d *diskStore) keys() ([]string, error) {
+	var keys []string
+	err := d.scan(func(key string, entry cacheEntry) {
+		keys = append(keys, key)
+	})
+	return keys, err
+}
+
+func (d *diskStore) remove(key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,179 @@
+package pokecache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Validators are the HTTP cache validators stored alongside a response.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// Response is the result of a FetchFunc. NotModified reports that the origin
+// confirmed the stale value is still current, in which case Val is unused.
+// NotFound reports that the origin has no value for the key at all.
+type Response struct {
+	Val         []byte
+	Validators  Validators
+	NotModified bool
+	NotFound    bool
+}
+
+// ErrNotFound is returned by GetOrFetch when the origin reported that a key
+// does not exist, either just now or within the negative TTL.
+var ErrNotFound = errors.New("not found")
+
+// FetchFunc loads a key from its origin, giving up when ctx is done. When an
+// expired entry is still around, stale holds its validators so the origin can
+// answer NotModified.
+type FetchFunc func(ctx context.Context, stale Validators) (Response, error)
+
+// WithRevalidation keeps expired entries for retain so GetOrFetch can
+// revalidate them with a conditional request instead of downloading them
+// again.
+func WithRevalidation(retain time.Duration) Option {
+	return func(o *options) {
+		o.staleRetention = max(o.staleRetention, retain)
+	}
+}
+
+// WithStaleWhileRevalidate lets GetOrFetch answer with an entry that expired
+// less than window ago while it is revalidated in the background.
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(o *options) {
+		o.staleWhileRevalidate = window
+		o.staleRetention = max(o.staleRetention, window)
+	}
+}
+
+// WithNegativeTTL remembers keys the origin reported as not found for ttl, so
+// repeated lookups fail right away without asking the origin again.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = ttl
+	}
+}
+
+// entryStore is the raw entry access a backend offers to the shared fetch
+// logic. lookup also returns expired entries that are kept for revalidation.
+type entryStore interface {
+	remover
+	lookup(key string) (cacheEntry, bool)
+	store(key string, entry cacheEntry)
+}
+
+// getOrFetch returns the cached value for key. On a miss fetch is called and
+// a successful result is stored for ttl. Expired entries are revalidated with
+// their validators, and concurrent misses for the same key share a single
+// fetch and its result, which runs with the context of the caller that
+// started it. Background revalidation outlives the caller's context but not
+// its values.
+func (c *core) getOrFetch(ctx context.Context, s entryStore, key string, ttl time.Duration, fetch FetchFunc) ([]byte, error) {
+	entry, exists := s.lookup(key)
+	now := c.now()
+	if exists && entry.negative {
+		if now.Before(entry.expiresAt) {
+			c.counters.negativeHits.Add(1)
+			return nil, notFound(key)
+		}
+		exists = false
+	}
+	if exists && now.Before(entry.expiresAt) {
+		if val, err := c.decode(s, key, entry); err == nil {
+			c.countHit(false)
+			return val, nil
+		}
+		exists = false
+	}
+	if exists && now.Before(entry.expiresAt.Add(c.staleWhileRevalidate)) {
+		if val, err := c.decode(s, key, entry); err == nil {
+			c.countHit(true)
+			go c.do(key, func() ([]byte, error) {
+				return c.refresh(context.WithoutCancel(ctx), s, key, ttl, fetch, entry, true)
+			})
+			return val, nil
+		}
+		exists = false
+	}
+	if exists && !c.intact(s, key, entry) {
+		// A damaged entry's validators cannot be trusted either.
+		exists = false
+	}
+
+	c.counters.misses.Add(1)
+	val, err := c.do(key, func() ([]byte, error) {
+		return c.refresh(ctx, s, key, ttl, fetch, entry, exists)
+	})
+	if err != nil && exists && !errors.Is(err, ErrNotFound) {
+		// Without a network, for example with an imported snapshot, a stale
+		// answer is more useful than none.
+		if stale, decodeErr := decodeChecked(entry); decodeErr == nil {
+			c.countHit(true)
+			return stale, nil
+		}
+	}
+	return val, err
+}
+
+// refresh fetches key from its origin. If hasStale is set the stale entry's
+// validators are sent along, and a NotModified answer extends its lifetime.
+func (c *core) refresh(ctx context.Context, s entryStore, key string, ttl time.Duration, fetch FetchFunc, stale cacheEntry, hasStale bool) ([]byte, error) {
+	var validators Validators
+	if hasStale {
+		validators = stale.validators
+	}
+	resp, err := fetch(ctx, validators)
+	if err != nil {
+		return nil, err
+	}
+	if resp.NotFound {
+		if c.negativeTTL > 0 {
+			now := c.now()
+			val := []byte{None.ID()}
+			s.store(key, cacheEntry{
+				createdAt: now,
+				expiresAt: now.Add(c.negativeTTL),
+				val:       val,
+				checksum:  checksum(val),
+				negative:  true,
+			})
+		}
+		return nil, notFound(key)
+	}
+	if !resp.NotModified {
+		entry, err := c.newEntry(resp.Val, ttl, resp.Validators)
+		if err != nil {
+			return nil, err
+		}
+		s.store(key, entry)
+		return resp.Val, nil
+	}
+	if !hasStale {
+		return nil, fmt.Errorf("pokecache: %s was not modified but is not cached", key)
+	}
+
+	now := c.now()
+	stale.createdAt = now
+	stale.expiresAt = now.Add(ttl)
+	if resp.Validators != (Validators{}) {
+		stale.validators = resp.Validators
+	}
+	s.store(key, stale)
+	c.counters.revalidations.Add(1)
+	return c.decode(s, key, stale)
+}
+
+func notFound(key string) error {
+	return fmt.Errorf("pokecache: %s: %w", key, ErrNotFound)
+}
+
+func (c *core) countHit(stale bool) {
+	c.counters.hits.Add(1)
+	if stale {
+		c.counters.staleHits.Add(1)
+	}
+}
@@ -0,0 +1,130 @@
+package pokecache
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// ErrCorrupt is matched by every CorruptError.
+var ErrCorrupt = errors.New("pokecache: corrupt entry")
+
+// CorruptError reports an entry that failed its checksum or could not be
+// decoded. The entry has already been removed when the error is reported.
+type CorruptError struct {
+	// Key is empty if the entry was too damaged to tell which key it held.
+	Key string
+	// Path is the file the entry was read from, if any.
+	Path string
+	Err  error
+}
+
+func (e *CorruptError) Error() string {
+	name := e.Key
+	if name == "" {
+		name = e.Path
+	}
+	return fmt.Sprintf("pokecache: corrupt entry %s: %v", name, e.Err)
+}
+
+func (e *CorruptError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CorruptError) Is(target error) bool {
+	return target == ErrCorrupt
+}
+
+// WithCorruptionHandler calls fn for every corrupt entry that is found and
+// evicted while the cache is used. Without a handler corrupt entries are only
+// counted in Stats.
+func WithCorruptionHandler(fn func(err *CorruptError)) Option {
+	return func(o *options) {
+		o.onCorrupt = fn
+	}
+}
+
+var errChecksum = errors.New("checksum mismatch")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+func checksum(val []byte) uint32 {
+	return crc32.Checksum(val, crcTable)
+}
+
+// decodeChecked verifies the checksum of entry before decoding its value.
+func decodeChecked(entry cacheEntry) ([]byte, error) {
+	if checksum(entry.val) != entry.checksum {
+		return nil, errChecksum
+	}
+	return decode(entry.val)
+}
+
+// remover is implemented by every backend, so that corrupt entries can be
+// evicted wherever they are found.
+type remover interface {
+	Delete(key string)
+}
+
+// decode returns the value of entry. A corrupt entry is evicted from s,
+// reported and returned as a *CorruptError.
+func (c *core) decode(s remover, key string, entry cacheEntry) ([]byte, error) {
+	val, err := decodeChecked(entry)
+	if err == nil {
+		return val, nil
+	}
+	s.Delete(key)
+	corrupt := &CorruptError{Key: key, Err: err}
+	c.reportCorrupt(corrupt)
+	return nil, corrupt
+}
+
+// intact reports whether entry still matches its checksum, evicting and
+// reporting it if it does not. Unlike decode it leaves the value encoded.
+func (c *core) intact(s remover, key string, entry cacheEntry) bool {
+	if checksum(entry.val) == entry.checksum {
+		return true
+	}
+	s.Delete(key)
+	c.reportCorrupt(&CorruptError{Key: key, Err: errChecksum})
+	return false
+}
+
+// readError handles an error returned by the disk tier. Corrupt files have
+// already been removed and are reported as such, anything else goes to the
+// error handler.
+func (c *core) readError(err error) {
+	var corrupt *CorruptError
+	if errors.As(err, &corrupt) {
+		c.reportCorrupt(corrupt)
+		return
+	}
+	c.reportError(err)
+}
+
+func (c *core) reportCorrupt(err *CorruptError) {
+	c.counters.corruptions.Add(1)
+	if c.onCorrupt != nil {
+		c.onCorrupt(err)
+	}
+}
+
+// verifier is implemented by backends that can check all of their entries.
+type verifier interface {
+	verify(report func(err *CorruptError)) error
+}
+
+// Verify checks every entry of c, in memory and on disk, removes the corrupt
+// ones and returns them. Entries found by Verify are counted in Stats but not
+// passed to the corruption handler, the caller already gets them.
+func Verify(c Cache) ([]*CorruptError, error) {
+	v, ok := c.(verifier)
+	if !ok {
+		return nil, fmt.Errorf("pokecache: %T does not support verification", c)
+	}
+	var corrupt []*CorruptError
+	err := v.verify(func(err *CorruptError) {
+		corrupt = append(corrupt, err)
+	})
+	return corrupt, err
+}
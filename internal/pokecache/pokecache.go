@@ -1,97 +1,296 @@
 package pokecache
 
 import (
-	"bytes"
-	"compress/gzip"
-	"fmt"
-	"io"
+	"container/list"
+	"context"
 	"sync"
 	"time"
 )
 
 type cacheEntry struct {
 	createdAt time.Time
+	expiresAt time.Time
 	val       []byte
+	rawSize   int
+	checksum  uint32
+	elem      *list.Element
+
+	validators Validators
+	// negative entries record that the origin has no value for the key.
+	negative bool
+}
+
+// MemoryCache keeps entries in maps that are reaped on a fixed interval and
+// optionally bounded in size and backed by a disk tier. Entries are encoded
+// and decoded outside of any lock.
+type MemoryCache struct {
+	core
+
+	shards   []*shard
+	interval time.Duration
+
+	done      chan struct{}
+	reapDone  chan struct{}
+	closeOnce sync.Once
+}
+
+// WithMaxEntries caps the number of in-memory entries. Once the limit is
+// reached the least recently used entry is evicted.
+func WithMaxEntries(n int) Option {
+	return func(o *options) {
+		o.maxEntries = n
+	}
 }
 
-type Cache struct {
-	m  map[string]cacheEntry
-	mu sync.Mutex
+// WithMaxBytes caps the total compressed size of in-memory entries. Least
+// recently used entries are evicted until the cache fits the budget again.
+func WithMaxBytes(n int) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithShards splits the cache into n independently locked shards to reduce
+// lock contention between goroutines. Size limits are divided evenly among
+// the shards, so eviction becomes least recently used per shard.
+func WithShards(n int) Option {
+	return func(o *options) {
+		o.shards = n
+	}
 }
 
-func NewCache(interval time.Duration) *Cache {
-	cache := &Cache{
-		m: make(map[string]cacheEntry),
+// WithDiskTier persists entries below dir so they survive restarts. Entries on
+// disk expire after ttl, independent of the in-memory reap interval.
+func WithDiskTier(dir string, ttl time.Duration) Option {
+	return func(o *options) {
+		o.disk = newDiskStore(dir, ttl)
+	}
+}
+
+// NewMemoryCache returns a cache whose entries live for interval and are
+// reaped every interval. A non-positive interval disables the reaper, so
+// expired entries stay in memory until they are evicted or overwritten.
+func NewMemoryCache(interval time.Duration, opts ...Option) *MemoryCache {
+	cache := &MemoryCache{
+		interval: interval,
+		done:     make(chan struct{}),
+		reapDone: make(chan struct{}),
+	}
+	cache.init(opts)
+	n := max(cache.options.shards, 1)
+	cache.shards = make([]*shard, n)
+	for i := range cache.shards {
+		cache.shards[i] = newShard(cache, perShard(cache.maxEntries, n), perShard(cache.maxBytes, n))
 	}
-	go cache.reapLoop(interval)
+	if interval <= 0 {
+		close(cache.reapDone)
+		return cache
+	}
+	// The ticker is created before the goroutine starts so that a fake clock
+	// never advances past a ticker it does not know about yet.
+	go cache.reapLoop(cache.clock.NewTicker(interval))
 	return cache
 }
 
-func (c *Cache) Add(key string, val []byte) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	createdAt := time.Now()
-	val, err := compress(val)
+// Close stops the reap loop. It is safe to call Close more than once.
+func (c *MemoryCache) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	<-c.reapDone
+	return nil
+}
+
+// Add stores val for the cache's default interval.
+func (c *MemoryCache) Add(key string, val []byte) {
+	c.AddWithTTL(key, val, c.interval)
+}
+
+// AddWithTTL stores val and keeps it in memory for ttl instead of the default
+// interval.
+func (c *MemoryCache) AddWithTTL(key string, val []byte, ttl time.Duration) {
+	entry, err := c.newEntry(val, ttl, Validators{})
 	if err != nil {
-		fmt.Println(err)
+		c.reportError(err)
 		return
 	}
-	newEntry := cacheEntry{
-		createdAt: createdAt,
-		val:       val,
+	c.store(key, entry)
+}
+
+// GetOrFetch returns the cached value for key, calling fetch on a miss.
+func (c *MemoryCache) GetOrFetch(ctx context.Context, key string, ttl time.Duration, fetch FetchFunc) ([]byte, error) {
+	return c.getOrFetch(ctx, c, key, ttl, fetch)
+}
+
+// store puts an already encoded entry into memory and the disk tier.
+func (c *MemoryCache) store(key string, entry cacheEntry) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	s.setLocked(key, entry)
+	s.mu.Unlock()
+
+	if c.disk != nil {
+		if err := c.disk.write(key, entry); err != nil {
+			c.reportError(err)
+		}
 	}
-	c.m[key] = newEntry
 }
 
-func (c *Cache) Get(key string) ([]byte, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	entry, exists := s.getLocked(key)
+	s.mu.Unlock()
 
-	entry, exists := c.m[key]
+	if !exists {
+		entry, exists = c.loadFromDisk(key)
+		exists = exists && c.now().Before(entry.expiresAt)
+	}
+	exists = exists && !entry.negative
+	c.countLookup(exists)
 	if !exists {
 		return nil, false
 	}
-	value, err := decompress(entry.val)
+	val, err := c.decode(c, key, entry)
+	return val, err == nil
+}
+
+// lookup returns the entry for key from memory or disk, including expired
+// entries that are kept for revalidation. A memory copy that expired is
+// looked up on disk again, since promotion caps it at the reap interval
+// while the entry itself may still be fresh.
+func (c *MemoryCache) lookup(key string) (cacheEntry, bool) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	entry, exists := s.lookupLocked(key)
+	s.mu.Unlock()
+	if exists && (c.disk == nil || c.now().Before(entry.expiresAt)) {
+		return entry, true
+	}
+	if promoted, ok := c.loadFromDisk(key); ok {
+		return promoted, true
+	}
+	return entry, exists
+}
+
+// loadFromDisk looks key up in the disk tier and promotes a hit back into
+// memory, where it is subject to the regular reap interval again. The entry
+// keeps its own expiry if that comes first, so an entry that expired while
+// it was only on disk comes back stale and is revalidated.
+func (c *MemoryCache) loadFromDisk(key string) (cacheEntry, bool) {
+	if c.disk == nil {
+		return cacheEntry{}, false
+	}
+	entry, exists, err := c.disk.read(key)
 	if err != nil {
-		fmt.Println(err)
-		return nil, false
+		c.readError(err)
+		return cacheEntry{}, false
+	}
+	if !exists {
+		return cacheEntry{}, false
 	}
-	return value, true
+	promoted := cacheEntry{
+		createdAt: entry.createdAt,
+		expiresAt: entry.expiresAt,
+		val:       entry.val,
+		rawSize:   entry.rawSize,
+		checksum:  entry.checksum,
+
+		validators: entry.validators,
+		negative:   entry.negative,
+	}
+	if limit := c.now().Add(c.interval); limit.Before(promoted.expiresAt) {
+		promoted.expiresAt = limit
+	}
+	s := c.shardFor(key)
+	s.mu.Lock()
+	s.setLocked(key, promoted)
+	s.mu.Unlock()
+	return promoted, true
 }
 
-func (c *Cache) reapLoop(interval time.Duration) {
+func (c *MemoryCache) reapLoop(ticker Ticker) {
+	defer close(c.reapDone)
+	defer ticker.Stop()
 	for {
-		time.Sleep(interval)
-		c.mu.Lock()
-		currentTime := time.Now()
-		for key, entry := range c.m {
-			if currentTime.Sub(entry.createdAt) >= interval {
-				delete(c.m, key)
-			}
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C():
+			c.reap()
 		}
-		c.mu.Unlock()
 	}
 }
 
-// Compress the data
-func compress(data []byte) ([]byte, error) {
-	var buf bytes.Buffer
-	gz := gzip.NewWriter(&buf)
-	if _, err := gz.Write(data); err != nil {
-		return nil, err
+func (c *MemoryCache) reap() {
+	for _, s := range c.shards {
+		s.reap()
 	}
-	if err := gz.Close(); err != nil {
-		return nil, err
+}
+
+// perShard divides a size limit over n shards, rounding up so that a
+// positive limit never becomes zero, which would mean unlimited.
+func perShard(limit, n int) int {
+	if limit <= 0 {
+		return 0
 	}
-	return buf.Bytes(), nil
+	return (limit + n - 1) / n
 }
 
-// Decompress the data
-func decompress(data []byte) ([]byte, error) {
-	r, err := gzip.NewReader(bytes.NewReader(data))
-	if err != nil {
-		return nil, err
+// verify checks every entry in memory and in the disk tier. Corrupt entries
+// are removed from the tier they were found in.
+func (c *MemoryCache) verify(report func(err *CorruptError)) error {
+	found := func(err *CorruptError) {
+		c.counters.corruptions.Add(1)
+		report(err)
+	}
+	for _, s := range c.shards {
+		s.mu.Lock()
+		entries := make(map[string]cacheEntry, len(s.m))
+		for key, entry := range s.m {
+			entries[key] = *entry
+		}
+		s.mu.Unlock()
+
+		for key, entry := range entries {
+			if _, err := decodeChecked(entry); err != nil {
+				s.mu.Lock()
+				s.removeLocked(key)
+				s.mu.Unlock()
+				found(&CorruptError{Key: key, Err: err})
+			}
+		}
+	}
+	if c.disk == nil {
+		return nil
+	}
+	return c.disk.verify(found)
+}
+
+// scan calls fn for every entry in memory and every entry that is only left
+// in the disk tier.
+func (c *MemoryCache) scan(fn func(key string, entry cacheEntry)) error {
+	entries := make(map[string]cacheEntry)
+	for _, s := range c.shards {
+		s.mu.Lock()
+		for key, entry := range s.m {
+			entries[key] = *entry
+		}
+		s.mu.Unlock()
+	}
+
+	if c.disk != nil {
+		err := c.disk.scan(func(key string, entry cacheEntry) {
+			if _, exists := entries[key]; !exists {
+				entries[key] = entry
+			}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for key, entry := range entries {
+		fn(key, entry)
 	}
-	defer r.Close()
-	return io.ReadAll(r)
+	return nil
 }
@@ -1,7 +1,13 @@
 package pokecache
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -24,7 +30,8 @@ func TestAddGet(t *testing.T) {
 
 	for i, c := range cases {
 		t.Run(fmt.Sprintf("Test case %v", i), func(t *testing.T) {
-			cache := NewCache(interval)
+			cache := NewMemoryCache(interval)
+			defer cache.Close()
 			cache.Add(c.key, c.val)
 			val, ok := cache.Get(c.key)
 			if !ok {
@@ -42,7 +49,9 @@ func TestAddGet(t *testing.T) {
 func TestReapLoop(t *testing.T) {
 	const baseTime = 20 * time.Millisecond
 	const waitTime = baseTime + 5*time.Millisecond
-	cache := NewCache(baseTime)
+	clock := newFakeClock()
+	cache := NewMemoryCache(baseTime, WithClock(clock))
+	defer cache.Close()
 	cache.Add("https://example.com", []byte("testdata"))
 
 	_, ok := cache.Get("https://example.com")
@@ -51,7 +60,7 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 
-	time.Sleep(waitTime)
+	clock.Advance(waitTime)
 
 	_, ok = cache.Get("https://example.com")
 	if ok {
@@ -59,3 +68,858 @@ func TestReapLoop(t *testing.T) {
 		return
 	}
 }
+
+func TestReapLoopRemovesExpiredEntries(t *testing.T) {
+	const baseTime = 20 * time.Millisecond
+	clock := newFakeClock()
+	cache := NewMemoryCache(baseTime, WithClock(clock))
+	cache.Add("https://example.com", []byte("testdata"))
+
+	clock.Advance(baseTime)
+	// Close waits for the reap loop, so the reap triggered by the tick above
+	// has finished once it returns.
+	cache.Close()
+
+	stats := cache.Stats()
+	if stats.Entries != 0 || stats.Expirations != 1 {
+		t.Errorf("expected the entry to be reaped, got %+v", stats)
+	}
+}
+
+func TestDiskTier(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+
+	first := NewMemoryCache(interval, WithDiskTier(dir, time.Hour))
+	defer first.Close()
+	first.Add("https://example.com", []byte("testdata"))
+
+	second := NewMemoryCache(interval, WithDiskTier(dir, time.Hour))
+	defer second.Close()
+	val, ok := second.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key on disk")
+		return
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected to find value")
+		return
+	}
+}
+
+func TestDiskTierExpiry(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+
+	first := NewMemoryCache(interval, WithDiskTier(dir, time.Nanosecond))
+	defer first.Close()
+	first.Add("https://example.com", []byte("testdata"))
+
+	second := NewMemoryCache(interval, WithDiskTier(dir, time.Nanosecond))
+	defer second.Close()
+	_, ok := second.Get("https://example.com")
+	if ok {
+		t.Errorf("expected expired disk entry to be a miss")
+		return
+	}
+}
+
+func TestDiskTierKeepsEntryTTL(t *testing.T) {
+	const interval = time.Hour
+	clock := newFakeClock()
+	dir := t.TempDir()
+	first := NewMemoryCache(interval, WithClock(clock), WithDiskTier(dir, 7*24*time.Hour))
+	defer first.Close()
+
+	var sent []Validators
+	fetch := func(_ context.Context, stale Validators) (Response, error) {
+		sent = append(sent, stale)
+		if stale.ETag == "v1" {
+			return Response{NotModified: true}, nil
+		}
+		return Response{Val: []byte("testdata"), Validators: Validators{ETag: "v1"}}, nil
+	}
+	if _, err := first.GetOrFetch(context.Background(), "https://example.com", time.Minute, fetch); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if _, ok := first.Get("https://example.com"); ok {
+		t.Errorf("expected an entry past its TTL to be a miss, even though it is still on disk")
+	}
+
+	// A restart must not make the entry fresh again either.
+	second := NewMemoryCache(interval, WithClock(clock), WithDiskTier(dir, 7*24*time.Hour))
+	defer second.Close()
+	if _, ok := second.Get("https://example.com"); ok {
+		t.Errorf("expected an entry reloaded from disk to keep its TTL")
+	}
+	val, err := second.GetOrFetch(context.Background(), "https://example.com", time.Minute, fetch)
+	if err != nil || string(val) != "testdata" {
+		t.Fatalf("expected the revalidated value, got %q %v", val, err)
+	}
+	if len(sent) != 2 || sent[1].ETag != "v1" {
+		t.Errorf("expected the entry reloaded from disk to be revalidated, fetched with %v", sent)
+	}
+
+	// An entry that outlives the reap interval stays fresh after it was
+	// promoted from disk, rather than being revalidated with the interval.
+	sent = nil
+	if _, err := second.GetOrFetch(context.Background(), "https://example.com/long", 6*time.Hour, fetch); err != nil {
+		t.Fatal(err)
+	}
+	third := NewMemoryCache(interval, WithClock(clock), WithDiskTier(dir, 7*24*time.Hour), WithRevalidation(24*time.Hour))
+	defer third.Close()
+	for range 3 {
+		if _, err := third.GetOrFetch(context.Background(), "https://example.com/long", 6*time.Hour, fetch); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(interval + time.Minute)
+	}
+	if len(sent) != 1 {
+		t.Errorf("expected a single fetch within the TTL, fetched with %v", sent)
+	}
+}
+
+func TestMaxEntriesEvictsLeastRecentlyUsed(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithMaxEntries(2))
+	defer cache.Close()
+	cache.Add("https://example.com/1", []byte("one"))
+	cache.Add("https://example.com/2", []byte("two"))
+
+	// Touch the first key so the second one becomes the eviction candidate.
+	if _, ok := cache.Get("https://example.com/1"); !ok {
+		t.Errorf("expected to find key")
+		return
+	}
+	cache.Add("https://example.com/3", []byte("three"))
+
+	if _, ok := cache.Get("https://example.com/2"); ok {
+		t.Errorf("expected least recently used key to be evicted")
+	}
+	for _, key := range []string{"https://example.com/1", "https://example.com/3"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected to find %s", key)
+		}
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	const interval = 5 * time.Second
+	val := []byte("testdata")
+	encoded, err := encode(Gzip, defaultMinCompressSize, val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := NewMemoryCache(interval, WithMaxBytes(2*len(encoded)))
+	defer cache.Close()
+	cache.Add("https://example.com/1", val)
+	cache.Add("https://example.com/2", val)
+	cache.Add("https://example.com/3", val)
+
+	if _, ok := cache.Get("https://example.com/1"); ok {
+		t.Errorf("expected oldest key to be evicted")
+	}
+	if _, ok := cache.Get("https://example.com/3"); !ok {
+		t.Errorf("expected newest key to be kept")
+	}
+}
+
+func TestAddWithTTL(t *testing.T) {
+	const baseTime = 20 * time.Millisecond
+	const waitTime = baseTime + 5*time.Millisecond
+	clock := newFakeClock()
+	cache := NewMemoryCache(baseTime, WithClock(clock))
+	defer cache.Close()
+	cache.AddWithTTL("https://example.com/short", []byte("testdata"), baseTime)
+	cache.AddWithTTL("https://example.com/long", []byte("testdata"), time.Hour)
+
+	clock.Advance(waitTime)
+
+	if _, ok := cache.Get("https://example.com/short"); ok {
+		t.Errorf("expected short lived key to expire")
+	}
+	if _, ok := cache.Get("https://example.com/long"); !ok {
+		t.Errorf("expected long lived key to survive the reap interval")
+	}
+}
+
+func TestCloseStopsReapLoop(t *testing.T) {
+	cache := NewMemoryCache(time.Millisecond)
+	cache.Close()
+	select {
+	case <-cache.reapDone:
+	default:
+		t.Errorf("expected reap loop to have stopped")
+	}
+	// Closing twice must not panic.
+	cache.Close()
+}
+
+func TestReapingDisabled(t *testing.T) {
+	clock := newFakeClock()
+	cache := NewMemoryCache(0, WithClock(clock))
+	cache.AddWithTTL("https://example.com", []byte("testdata"), time.Hour)
+	clock.Advance(time.Minute)
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected the entry to live for its own TTL")
+	}
+	cache.Close()
+	cache.Close()
+}
+
+func TestStats(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval)
+	defer cache.Close()
+	cache.Add("https://example.com", []byte("testdata"))
+	cache.Get("https://example.com")
+	cache.Get("https://example.com/missing")
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("expected 1 hit and 1 miss, got %d and %d", stats.Hits, stats.Misses)
+	}
+	if stats.Entries != 1 {
+		t.Errorf("expected 1 entry, got %d", stats.Entries)
+	}
+	if stats.UncompressedBytes != len("testdata") {
+		t.Errorf("expected %d uncompressed bytes, got %d", len("testdata"), stats.UncompressedBytes)
+	}
+}
+
+func TestPurgePrefix(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithDiskTier(t.TempDir(), time.Hour))
+	defer cache.Close()
+	cache.Add("https://example.com/pokemon/1", []byte("bulbasaur"))
+	cache.Add("https://example.com/pokemon/2", []byte("ivysaur"))
+	cache.Add("https://example.com/location-area/1", []byte("canalave-city-area"))
+
+	if purged := PurgePrefix(cache, "https://example.com/pokemon/"); purged != 2 {
+		t.Errorf("expected 2 purged keys, got %d", purged)
+	}
+	keys := cache.Keys()
+	if len(keys) != 1 || keys[0] != "https://example.com/location-area/1" {
+		t.Errorf("unexpected keys after purge: %v", keys)
+	}
+
+	Clear(cache)
+	if keys := cache.Keys(); len(keys) != 0 {
+		t.Errorf("expected no keys after clear, got %v", keys)
+	}
+}
+
+func TestGetOrFetchDeduplicatesConcurrentMisses(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval)
+	defer cache.Close()
+
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func(context.Context, Validators) (Response, error) {
+		fetches.Add(1)
+		<-release
+		return Response{Val: []byte("testdata")}, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val, err := cache.GetOrFetch(context.Background(), "https://example.com", interval, fetch)
+			if err != nil || string(val) != "testdata" {
+				t.Errorf("unexpected result %q, %v", val, err)
+			}
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Errorf("expected a single fetch, got %d", n)
+	}
+}
+
+func TestGetOrFetchDoesNotCacheErrors(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval)
+	defer cache.Close()
+
+	_, err := cache.GetOrFetch(context.Background(), "https://example.com", interval, func(context.Context, Validators) (Response, error) {
+		return Response{}, fmt.Errorf("network down")
+	})
+	if err == nil {
+		t.Errorf("expected fetch error")
+	}
+	if _, ok := cache.Get("https://example.com"); ok {
+		t.Errorf("expected failed fetch not to be cached")
+	}
+}
+
+func TestGetOrFetchRevalidatesExpiredEntries(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithRevalidation(time.Hour))
+	defer cache.Close()
+
+	etag := `"v1"`
+	_, err := cache.GetOrFetch(context.Background(), "https://example.com", time.Nanosecond, func(context.Context, Validators) (Response, error) {
+		return Response{Val: []byte("testdata"), Validators: Validators{ETag: etag}}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sent Validators
+	val, err := cache.GetOrFetch(context.Background(), "https://example.com", interval, func(_ context.Context, stale Validators) (Response, error) {
+		sent = stale
+		return Response{NotModified: true}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent.ETag != etag {
+		t.Errorf("expected stale ETag %s to be sent, got %q", etag, sent.ETag)
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected revalidated value, got %q", val)
+	}
+	if n := cache.Stats().Revalidations; n != 1 {
+		t.Errorf("expected 1 revalidation, got %d", n)
+	}
+	if _, ok := cache.Get("https://example.com"); !ok {
+		t.Errorf("expected revalidated entry to be fresh again")
+	}
+}
+
+func TestGetOrFetchServesStaleWhileRevalidating(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithStaleWhileRevalidate(time.Hour))
+	defer cache.Close()
+
+	_, err := cache.GetOrFetch(context.Background(), "https://example.com", time.Nanosecond, func(context.Context, Validators) (Response, error) {
+		return Response{Val: []byte("old")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	val, err := cache.GetOrFetch(context.Background(), "https://example.com", interval, func(context.Context, Validators) (Response, error) {
+		<-release
+		return Response{Val: []byte("new")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(val) != "old" {
+		t.Errorf("expected stale value while revalidating, got %q", val)
+	}
+	close(release)
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if val, ok := cache.Get("https://example.com"); ok && string(val) == "new" {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Errorf("expected background revalidation to store the new value")
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	val := bytes.Repeat([]byte("testdata"), 100)
+	for _, codec := range []Codec{None, Gzip, Zlib, Flate} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			encoded, err := encode(codec, 0, val)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encoded[0] != codec.ID() {
+				t.Errorf("expected marker %d, got %d", codec.ID(), encoded[0])
+			}
+			decoded, err := decode(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decoded, val) {
+				t.Errorf("expected to find value")
+			}
+		})
+	}
+}
+
+func TestSmallValuesAreStoredRaw(t *testing.T) {
+	encoded, err := encode(Gzip, 16, []byte("testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded[0] != None.ID() {
+		t.Errorf("expected small value to be stored raw, got codec %d", encoded[0])
+	}
+}
+
+func TestMixedCodecsOnDisk(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+	val := bytes.Repeat([]byte("testdata"), 100)
+
+	writer := NewMemoryCache(interval, WithDiskTier(dir, time.Hour), WithCodec(Zlib))
+	defer writer.Close()
+	writer.Add("https://example.com", val)
+
+	reader := NewMemoryCache(interval, WithDiskTier(dir, time.Hour), WithCodec(Flate))
+	defer reader.Close()
+	got, ok := reader.Get("https://example.com")
+	if !ok {
+		t.Errorf("expected to find key on disk")
+		return
+	}
+	if !bytes.Equal(got, val) {
+		t.Errorf("expected to find value")
+	}
+}
+
+func TestBackends(t *testing.T) {
+	const interval = 5 * time.Second
+	backends := map[string]func(t *testing.T) Cache{
+		"memory": func(t *testing.T) Cache {
+			return NewMemoryCache(interval)
+		},
+		"dir": func(t *testing.T) Cache {
+			return NewDirCache(t.TempDir(), interval)
+		},
+	}
+	for name, newCache := range backends {
+		t.Run(name, func(t *testing.T) {
+			cache := newCache(t)
+			defer cache.Close()
+			cache.Add("https://example.com/1", []byte("one"))
+			cache.AddWithTTL("https://example.com/2", []byte("two"), time.Nanosecond)
+
+			val, ok := cache.Get("https://example.com/1")
+			if !ok || string(val) != "one" {
+				t.Errorf("expected to find value, got %q", val)
+			}
+			if _, ok := cache.Get("https://example.com/2"); ok {
+				t.Errorf("expected expired key to be a miss")
+			}
+
+			cache.Delete("https://example.com/1")
+			if _, ok := cache.Get("https://example.com/1"); ok {
+				t.Errorf("expected deleted key to be a miss")
+			}
+
+			val, err := cache.GetOrFetch(context.Background(), "https://example.com/3", interval, func(context.Context, Validators) (Response, error) {
+				return Response{Val: []byte("three")}, nil
+			})
+			if err != nil || string(val) != "three" {
+				t.Errorf("unexpected fetch result %q, %v", val, err)
+			}
+			keys := cache.Keys()
+			if len(keys) != 1 || keys[0] != "https://example.com/3" {
+				t.Errorf("unexpected keys: %v", keys)
+			}
+			if stats := cache.Stats(); stats.Entries != 1 {
+				t.Errorf("expected 1 entry, got %d", stats.Entries)
+			}
+		})
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	const interval = 5 * time.Second
+	source := NewMemoryCache(interval)
+	defer source.Close()
+	source.Add("https://example.com/1", []byte("one"))
+	source.Add("https://example.com/2", []byte("two"))
+
+	var buf bytes.Buffer
+	exported, err := Export(source, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 2 {
+		t.Errorf("expected 2 exported entries, got %d", exported)
+	}
+
+	target := NewDirCache(t.TempDir(), interval)
+	imported, err := Import(target, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != 2 {
+		t.Errorf("expected 2 imported entries, got %d", imported)
+	}
+	val, ok := target.Get("https://example.com/2")
+	if !ok || string(val) != "two" {
+		t.Errorf("expected to find imported value, got %q", val)
+	}
+}
+
+func TestGetOrFetchServesStaleOnError(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithRevalidation(time.Hour))
+	defer cache.Close()
+	cache.AddWithTTL("https://example.com", []byte("testdata"), time.Nanosecond)
+
+	val, err := cache.GetOrFetch(context.Background(), "https://example.com", interval, func(context.Context, Validators) (Response, error) {
+		return Response{}, fmt.Errorf("network down")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(val) != "testdata" {
+		t.Errorf("expected stale value, got %q", val)
+	}
+}
+
+func TestNegativeCaching(t *testing.T) {
+	const interval = 5 * time.Second
+	clock := newFakeClock()
+	cache := NewMemoryCache(interval, WithClock(clock), WithNegativeTTL(time.Minute))
+	defer cache.Close()
+
+	fetches := 0
+	fetch := func(context.Context, Validators) (Response, error) {
+		fetches++
+		return Response{NotFound: true}, nil
+	}
+
+	for range 2 {
+		_, err := cache.GetOrFetch(context.Background(), "https://example.com/missing", interval, fetch)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("expected the second lookup to be answered from the negative entry, got %d fetches", fetches)
+	}
+	if _, ok := cache.Get("https://example.com/missing"); ok {
+		t.Errorf("expected a negative entry to be a miss for Get")
+	}
+
+	clock.Advance(time.Minute)
+	cache.GetOrFetch(context.Background(), "https://example.com/missing", interval, fetch)
+	if fetches != 2 {
+		t.Errorf("expected an expired negative entry to be fetched again, got %d fetches", fetches)
+	}
+}
+
+func TestShardedCache(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithShards(8), WithMaxEntries(64))
+	defer cache.Close()
+
+	for i := range 100 {
+		cache.Add(fmt.Sprintf("https://example.com/%d", i), []byte("testdata"))
+	}
+	stats := cache.Stats()
+	if stats.Entries > 64 {
+		t.Errorf("expected at most 64 entries across shards, got %d", stats.Entries)
+	}
+	if stats.Entries+stats.Evictions != 100 {
+		t.Errorf("expected every entry to be kept or evicted, got %+v", stats)
+	}
+	if len(cache.Keys()) != stats.Entries {
+		t.Errorf("expected Keys to cover every shard")
+	}
+
+	cache.Add("https://example.com/kept", []byte("testdata"))
+	if val, ok := cache.Get("https://example.com/kept"); !ok || string(val) != "testdata" {
+		t.Errorf("expected to find value")
+	}
+}
+
+func benchmarkParallel(b *testing.B, shards int, codec Codec, op func(cache *MemoryCache, key string)) {
+	const interval = 5 * time.Minute
+	cache := NewMemoryCache(interval, WithShards(shards), WithCodec(codec))
+	defer cache.Close()
+
+	val := bytes.Repeat([]byte("testdata"), 512)
+	keys := make([]string, 1024)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("https://example.com/pokemon/%d", i)
+		cache.Add(keys[i], val)
+	}
+
+	var next atomic.Int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			op(cache, keys[next.Add(1)%int64(len(keys))])
+		}
+	})
+}
+
+// The none codec leaves locking as the main cost, which is what sharding
+// improves. With gzip the numbers show the effect of compressing outside the
+// lock.
+func BenchmarkGetParallel(b *testing.B) {
+	for _, codec := range []Codec{None, Gzip} {
+		for _, shards := range []int{1, 16} {
+			b.Run(fmt.Sprintf("codec=%s/shards=%d", codec.Name(), shards), func(b *testing.B) {
+				benchmarkParallel(b, shards, codec, func(cache *MemoryCache, key string) {
+					cache.Get(key)
+				})
+			})
+		}
+	}
+}
+
+func BenchmarkAddParallel(b *testing.B) {
+	val := bytes.Repeat([]byte("testdata"), 512)
+	for _, codec := range []Codec{None, Gzip} {
+		for _, shards := range []int{1, 16} {
+			b.Run(fmt.Sprintf("codec=%s/shards=%d", codec.Name(), shards), func(b *testing.B) {
+				benchmarkParallel(b, shards, codec, func(cache *MemoryCache, key string) {
+					cache.Add(key, val)
+				})
+			})
+		}
+	}
+}
+
+// fakeClock only moves when Advance is called. Every tick is delivered
+// synchronously, so the reap loop has received it once Advance returns.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) NewTicker(d time.Duration) Ticker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ticker := &fakeTicker{
+		c:       make(chan time.Time),
+		stopped: make(chan struct{}),
+		period:  d,
+		next:    f.now.Add(d),
+	}
+	f.tickers = append(f.tickers, ticker)
+	return ticker
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+	tickers := append([]*fakeTicker(nil), f.tickers...)
+	f.mu.Unlock()
+
+tickers:
+	for _, ticker := range tickers {
+		for !ticker.next.After(now) {
+			select {
+			case ticker.c <- ticker.next:
+			case <-ticker.stopped:
+				continue tickers
+			}
+			ticker.next = ticker.next.Add(ticker.period)
+		}
+	}
+}
+
+type fakeTicker struct {
+	c        chan time.Time
+	stopped  chan struct{}
+	stopOnce sync.Once
+	period   time.Duration
+	next     time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.stopOnce.Do(func() {
+		close(t.stopped)
+	})
+}
+
+func TestDiskErrorsGoToHandler(t *testing.T) {
+	const interval = 5 * time.Second
+	// A file where the cache directory should be makes every write fail.
+	dir := t.TempDir() + "/not-a-dir"
+	if err := os.WriteFile(dir, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported []error
+	handler := WithErrorHandler(func(err error) {
+		reported = append(reported, err)
+	})
+	caches := map[string]Cache{
+		"memory": NewMemoryCache(interval, WithDiskTier(dir, time.Hour), handler),
+		"dir":    NewDirCache(dir, interval, handler),
+	}
+	for name, cache := range caches {
+		t.Run(name, func(t *testing.T) {
+			defer cache.Close()
+			reported = nil
+			cache.Add("https://example.com", []byte("testdata"))
+			if len(reported) != 1 {
+				t.Errorf("expected the failed write to be reported once, got %v", reported)
+			}
+			if stats := cache.Stats(); stats.Errors < 1 {
+				t.Errorf("expected the failed write to be counted, got %+v", stats)
+			}
+		})
+	}
+}
+
+func TestCorruptEntries(t *testing.T) {
+	const interval = 5 * time.Second
+	dir := t.TempDir()
+	var reported []*CorruptError
+	cache := NewDirCache(dir, interval, WithCorruptionHandler(func(err *CorruptError) {
+		reported = append(reported, err)
+	}))
+	cache.Add("https://example.com/1", []byte("one"))
+	cache.Add("https://example.com/2", []byte("two"))
+
+	// Flip the last byte of the value on disk.
+	path := cache.disk.path("https://example.com/1")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := cache.Get("https://example.com/1"); ok {
+		t.Fatal("expected corrupt entry to be a miss")
+	}
+	if len(reported) != 1 || reported[0].Key != "https://example.com/1" || !errors.Is(reported[0], ErrCorrupt) {
+		t.Fatalf("expected the corrupt entry to be reported, got %v", reported)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected corrupt entry to be removed, got %v", err)
+	}
+	if val, ok := cache.Get("https://example.com/2"); !ok || string(val) != "two" {
+		t.Errorf("expected intact entry to survive, got %q", val)
+	}
+
+	// Truncate the other entry and find it with Verify.
+	path = cache.disk.path("https://example.com/2")
+	if err := os.WriteFile(path, []byte{diskVersion, 0}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	corrupt, err := Verify(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 1 || corrupt[0].Path != path {
+		t.Errorf("expected Verify to find the truncated entry, got %v", corrupt)
+	}
+	if stats := cache.Stats(); stats.Corruptions != 2 || stats.Entries != 0 {
+		t.Errorf("expected 2 corruptions and no entries, got %+v", stats)
+	}
+}
+
+func TestVerifyMemoryCache(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval, WithShards(4))
+	defer cache.Close()
+	cache.Add("https://example.com/1", []byte("one"))
+	cache.Add("https://example.com/2", []byte("two"))
+
+	s := cache.shardFor("https://example.com/1")
+	s.mu.Lock()
+	s.m["https://example.com/1"].val[1] ^= 0xff
+	s.mu.Unlock()
+
+	corrupt, err := Verify(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(corrupt) != 1 || corrupt[0].Key != "https://example.com/1" {
+		t.Fatalf("expected one corrupt entry, got %v", corrupt)
+	}
+	if keys := cache.Keys(); len(keys) != 1 || keys[0] != "https://example.com/2" {
+		t.Errorf("expected only the intact entry to remain, got %v", keys)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	aliases := NewAliases()
+	aliases.Add("https://example.com/pokemon/pikachu", "https://example.com/pokemon/25")
+
+	if key := aliases.Resolve("https://example.com/pokemon/pikachu"); key != "https://example.com/pokemon/25" {
+		t.Errorf("expected alias to resolve to the cached key, got %s", key)
+	}
+	if key := aliases.Resolve("https://example.com/pokemon/1"); key != "https://example.com/pokemon/1" {
+		t.Errorf("expected unknown key to resolve to itself, got %s", key)
+	}
+}
+
+func TestPersistentAliasesSurviveRestart(t *testing.T) {
+	const interval = time.Hour
+	dir := t.TempDir()
+	first := NewMemoryCache(interval, WithDiskTier(dir, 24*time.Hour))
+	NewPersistentAliases(first, 24*time.Hour).Add("https://example.com/pokemon/pikachu", "https://example.com/pokemon/25")
+	first.Close()
+
+	second := NewMemoryCache(interval, WithDiskTier(dir, 24*time.Hour))
+	defer second.Close()
+	aliases := NewPersistentAliases(second, 24*time.Hour)
+	if key := aliases.Resolve("https://example.com/pokemon/pikachu"); key != "https://example.com/pokemon/25" {
+		t.Errorf("expected the alias to be read back from disk, got %s", key)
+	}
+	if key := aliases.Resolve("https://example.com/pokemon/1"); key != "https://example.com/pokemon/1" {
+		t.Errorf("expected unknown key to resolve to itself, got %s", key)
+	}
+	if stats := second.Stats(); stats.Hits != 0 || stats.Misses != 0 {
+		t.Errorf("expected alias lookups not to count as cache lookups, got %+v", stats)
+	}
+}
+
+func TestTypedCacheDecodesOnce(t *testing.T) {
+	const interval = 5 * time.Second
+	cache := NewMemoryCache(interval)
+	defer cache.Close()
+
+	decodes := 0
+	typed := NewTypedCache(cache, func(body []byte) (string, error) {
+		decodes++
+		return string(body), nil
+	})
+	fetch := func(context.Context, Validators) (Response, error) {
+		return Response{Val: []byte("testdata")}, nil
+	}
+
+	for range 3 {
+		val, err := typed.GetOrFetch(context.Background(), "https://example.com", interval, fetch)
+		if err != nil || val != "testdata" {
+			t.Errorf("unexpected result %q, %v", val, err)
+		}
+	}
+	if decodes != 1 {
+		t.Errorf("expected a single decode, got %d", decodes)
+	}
+
+	typed.Delete("https://example.com")
+	if _, err := typed.GetOrFetch(context.Background(), "https://example.com", interval, fetch); err != nil {
+		t.Fatal(err)
+	}
+	if decodes != 2 {
+		t.Errorf("expected a deleted value to be decoded again from the byte cache, got %d decodes", decodes)
+	}
+}
@@ -0,0 +1,129 @@
+package pokecache
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+)
+
+// shard is one independently locked part of a MemoryCache. Keys are spread
+// over the shards by hash, so callers working on different keys rarely wait
+// for each other. Size limits apply per shard.
+type shard struct {
+	cache *MemoryCache
+
+	mu sync.Mutex
+	m  map[string]*cacheEntry
+
+	// lru orders keys from most to least recently used. It is only consulted
+	// when maxEntries or maxBytes is set.
+	lru        *list.List
+	size       int
+	rawSize    int
+	maxEntries int
+	maxBytes   int
+}
+
+func newShard(cache *MemoryCache, maxEntries, maxBytes int) *shard {
+	return &shard{
+		cache:      cache,
+		m:          make(map[string]*cacheEntry),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+	}
+}
+
+// shardFor returns the shard responsible for key.
+func (c *MemoryCache) shardFor(key string) *shard {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// getLocked returns the unexpired entry for key and marks it as most recently
+// used. s.mu must be held.
+func (s *shard) getLocked(key string) (cacheEntry, bool) {
+	entry, exists := s.lookupLocked(key)
+	if !exists || !s.cache.now().Before(entry.expiresAt) {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+// lookupLocked is like getLocked but also returns expired entries that are
+// still kept around for revalidation. Entries past that window are dropped.
+// s.mu must be held.
+func (s *shard) lookupLocked(key string) (cacheEntry, bool) {
+	entry, exists := s.m[key]
+	if !exists {
+		return cacheEntry{}, false
+	}
+	if !s.cache.now().Before(entry.expiresAt.Add(s.cache.staleRetention)) {
+		s.removeLocked(key)
+		s.cache.counters.expirations.Add(1)
+		return cacheEntry{}, false
+	}
+	s.lru.MoveToFront(entry.elem)
+	return *entry, true
+}
+
+// setLocked stores entry under key and evicts least recently used entries
+// until the configured limits are met again. s.mu must be held.
+func (s *shard) setLocked(key string, entry cacheEntry) {
+	s.removeLocked(key)
+	if s.maxBytes > 0 && len(entry.val) > s.maxBytes {
+		return
+	}
+	entry.elem = s.lru.PushFront(key)
+	s.m[key] = &entry
+	s.size += len(entry.val)
+	s.rawSize += entry.rawSize
+	s.evictLocked()
+}
+
+// removeLocked drops key from the shard. s.mu must be held.
+func (s *shard) removeLocked(key string) {
+	entry, exists := s.m[key]
+	if !exists {
+		return
+	}
+	s.lru.Remove(entry.elem)
+	s.size -= len(entry.val)
+	s.rawSize -= entry.rawSize
+	delete(s.m, key)
+}
+
+func (s *shard) evictLocked() {
+	for s.overLimit() {
+		oldest := s.lru.Back()
+		if oldest == nil {
+			return
+		}
+		s.removeLocked(oldest.Value.(string))
+		s.cache.counters.evictions.Add(1)
+	}
+}
+
+func (s *shard) overLimit() bool {
+	if s.maxEntries > 0 && len(s.m) > s.maxEntries {
+		return true
+	}
+	return s.maxBytes > 0 && s.size > s.maxBytes
+}
+
+// reap drops every entry that is past its expiry and stale retention.
+func (s *shard) reap() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	currentTime := s.cache.now()
+	for key, entry := range s.m {
+		if !currentTime.Before(entry.expiresAt.Add(s.cache.staleRetention)) {
+			s.removeLocked(key)
+			s.cache.counters.expirations.Add(1)
+		}
+	}
+}
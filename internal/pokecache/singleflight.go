@@ -0,0 +1,36 @@
+package pokecache
+
+import "sync"
+
+// call is a fetch that is in progress for a single key. Callers that miss the
+// cache while it is running wait for it instead of starting their own.
+type call struct {
+	wg  sync.WaitGroup
+	val []byte
+	err error
+}
+
+// do runs fn for key unless a call for the same key is already in flight, in
+// which case it waits for that call and shares its result.
+func (c *core) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	c.flightMu.Lock()
+	if inflight, ok := c.inflight[key]; ok {
+		c.flightMu.Unlock()
+		inflight.wg.Wait()
+		return inflight.val, inflight.err
+	}
+	cl := &call{}
+	cl.wg.Add(1)
+	c.inflight[key] = cl
+	c.flightMu.Unlock()
+
+	defer func() {
+		c.flightMu.Lock()
+		delete(c.inflight, key)
+		c.flightMu.Unlock()
+		cl.wg.Done()
+	}()
+
+	cl.val, cl.err = fn()
+	return cl.val, cl.err
+}
@@ -0,0 +1,104 @@
+package pokecache
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// snapshotter is implemented by backends whose raw entries can be exported
+// and imported.
+type snapshotter interface {
+	entryStore
+	scan(fn func(key string, entry cacheEntry)) error
+	now() time.Time
+	reportCorrupt(err *CorruptError)
+}
+
+// Export writes every entry of c to w as a tar archive with one member per
+// key. Values are written in their stored, encoded form together with their
+// timestamps and validators. It reports the number of exported entries.
+func Export(c Cache, w io.Writer) (int, error) {
+	s, ok := c.(snapshotter)
+	if !ok {
+		return 0, fmt.Errorf("pokecache: %T does not support snapshots", c)
+	}
+	tw := tar.NewWriter(w)
+	exported := 0
+	var writeErr error
+	err := s.scan(func(key string, entry cacheEntry) {
+		if writeErr != nil {
+			return
+		}
+		data := encodeDiskEntry(key, entry)
+		sum := sha256.Sum256([]byte(key))
+		header := &tar.Header{
+			Name:    hex.EncodeToString(sum[:]),
+			Mode:    0o644,
+			Size:    int64(len(data)),
+			ModTime: entry.createdAt,
+		}
+		if writeErr = tw.WriteHeader(header); writeErr != nil {
+			return
+		}
+		if _, writeErr = tw.Write(data); writeErr != nil {
+			return
+		}
+		exported++
+	})
+	if err != nil {
+		return exported, err
+	}
+	if writeErr != nil {
+		return exported, writeErr
+	}
+	return exported, tw.Close()
+}
+
+// Import loads a snapshot written by Export into c. Imported entries keep
+// their original lifetime but start it anew, so an old snapshot is still
+// useful on a machine without network access. Damaged entries are skipped and
+// passed to the corruption handler. It reports the number of imported
+// entries.
+func Import(c Cache, r io.Reader) (int, error) {
+	s, ok := c.(snapshotter)
+	if !ok {
+		return 0, fmt.Errorf("pokecache: %T does not support snapshots", c)
+	}
+	tr := tar.NewReader(r)
+	imported := 0
+	now := s.now()
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return imported, nil
+		}
+		if err != nil {
+			return imported, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return imported, err
+		}
+		key, entry, err := decodeDiskEntry(data)
+		if errors.Is(err, errDiskVersion) {
+			return imported, err
+		}
+		if err == nil {
+			_, err = decodeChecked(entry)
+		}
+		if err != nil {
+			s.reportCorrupt(&CorruptError{Key: key, Path: header.Name, Err: err})
+			continue
+		}
+		lifetime := entry.expiresAt.Sub(entry.createdAt)
+		entry.createdAt = now
+		entry.expiresAt = now.Add(lifetime)
+		s.store(key, entry)
+		imported++
+	}
+}
@@ -0,0 +1,110 @@
+package pokecache
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// Stats describes how a Cache has been used since it was created. For a
+// MemoryCache, byte and entry counts only cover the in-memory tier.
+type Stats struct {
+	Hits          int
+	StaleHits     int
+	NegativeHits  int
+	Misses        int
+	Revalidations int
+	Evictions     int
+	Expirations   int
+	Corruptions   int
+	Errors        int
+
+	Entries           int
+	CompressedBytes   int
+	UncompressedBytes int
+}
+
+// counters are updated without a lock, so that keeping statistics does not
+// serialize callers that otherwise only contend on their own shard.
+type counters struct {
+	hits          atomic.Int64
+	staleHits     atomic.Int64
+	negativeHits  atomic.Int64
+	misses        atomic.Int64
+	revalidations atomic.Int64
+	evictions     atomic.Int64
+	expirations   atomic.Int64
+	corruptions   atomic.Int64
+	errors        atomic.Int64
+}
+
+func (c *counters) snapshot() Stats {
+	return Stats{
+		Hits:          int(c.hits.Load()),
+		StaleHits:     int(c.staleHits.Load()),
+		NegativeHits:  int(c.negativeHits.Load()),
+		Misses:        int(c.misses.Load()),
+		Revalidations: int(c.revalidations.Load()),
+		Evictions:     int(c.evictions.Load()),
+		Expirations:   int(c.expirations.Load()),
+		Corruptions:   int(c.corruptions.Load()),
+		Errors:        int(c.errors.Load()),
+	}
+}
+
+func (c *MemoryCache) Stats() Stats {
+	stats := c.counters.snapshot()
+	for _, s := range c.shards {
+		s.mu.Lock()
+		stats.Entries += len(s.m)
+		stats.CompressedBytes += s.size
+		stats.UncompressedBytes += s.rawSize
+		s.mu.Unlock()
+	}
+	return stats
+}
+
+// Keys returns the sorted keys held in memory or on disk.
+func (c *MemoryCache) Keys() []string {
+	seen := make(map[string]bool)
+	currentTime := c.now()
+	for _, s := range c.shards {
+		s.mu.Lock()
+		for key, entry := range s.m {
+			if currentTime.Before(entry.expiresAt.Add(c.staleRetention)) {
+				seen[key] = true
+			}
+		}
+		s.mu.Unlock()
+	}
+
+	if c.disk != nil {
+		diskKeys, err := c.disk.keys()
+		if err != nil {
+			c.reportError(err)
+		}
+		for _, key := range diskKeys {
+			seen[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Delete removes key from memory and disk.
+func (c *MemoryCache) Delete(key string) {
+	s := c.shardFor(key)
+	s.mu.Lock()
+	s.removeLocked(key)
+	s.mu.Unlock()
+
+	if c.disk != nil {
+		if err := c.disk.remove(key); err != nil {
+			c.reportError(err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package pokecache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TypedCache keeps decoded values on top of a byte Cache, so values that are
+// looked up often are not decompressed and unmarshalled every time. It falls
+// back to the byte cache, and through it to the origin, only on a miss.
+// Only WithMaxEntries and WithClock apply to a TypedCache.
+type TypedCache[T any] struct {
+	bytes  Cache
+	decode func([]byte) (T, error)
+	opts   options
+
+	mu  sync.Mutex
+	m   map[string]*typedEntry[T]
+	lru *list.List
+}
+
+type typedEntry[T any] struct {
+	val       T
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+func NewTypedCache[T any](bytes Cache, decode func([]byte) (T, error), opts ...Option) *TypedCache[T] {
+	typed := &TypedCache[T]{
+		bytes:  bytes,
+		decode: decode,
+		m:      make(map[string]*typedEntry[T]),
+		lru:    list.New(),
+	}
+	typed.opts.clock = realClock{}
+	for _, opt := range opts {
+		opt(&typed.opts)
+	}
+	return typed
+}
+
+// GetOrFetch returns the decoded value for key. On a miss the bytes are taken
+// from the byte cache, or fetched into it, and the decoded value is kept for
+// ttl.
+func (t *TypedCache[T]) GetOrFetch(ctx context.Context, key string, ttl time.Duration, fetch FetchFunc) (T, error) {
+	if val, ok := t.get(key); ok {
+		return val, nil
+	}
+	body, err := t.bytes.GetOrFetch(ctx, key, ttl, fetch)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	val, err := t.decode(body)
+	if err != nil {
+		return val, err
+	}
+	t.add(key, val, ttl)
+	return val, nil
+}
+
+// Delete drops the decoded value for key. The byte cache is left untouched.
+func (t *TypedCache[T]) Delete(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(key)
+}
+
+// PurgePrefix drops every decoded value whose key starts with prefix.
+func (t *TypedCache[T]) PurgePrefix(prefix string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for key := range t.m {
+		if strings.HasPrefix(key, prefix) {
+			t.removeLocked(key)
+		}
+	}
+}
+
+func (t *TypedCache[T]) get(key string) (T, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, exists := t.m[key]
+	if !exists {
+		var zero T
+		return zero, false
+	}
+	if !t.opts.clock.Now().Before(entry.expiresAt) {
+		t.removeLocked(key)
+		var zero T
+		return zero, false
+	}
+	t.lru.MoveToFront(entry.elem)
+	return entry.val, true
+}
+
+func (t *TypedCache[T]) add(key string, val T, ttl time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(key)
+	t.m[key] = &typedEntry[T]{
+		val:       val,
+		expiresAt: t.opts.clock.Now().Add(ttl),
+		elem:      t.lru.PushFront(key),
+	}
+	for t.opts.maxEntries > 0 && len(t.m) > t.opts.maxEntries {
+		t.removeLocked(t.lru.Back().Value.(string))
+	}
+}
+
+func (t *TypedCache[T]) removeLocked(key string) {
+	entry, exists := t.m[key]
+	if !exists {
+		return
+	}
+	t.lru.Remove(entry.elem)
+	delete(t.m, key)
+}
@@ -0,0 +1,92 @@
+package pokedexapi
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultBatchWorkers is the number of requests a batch keeps in flight when
+// the caller does not say.
+const DefaultBatchWorkers = 8
+
+// Result is the outcome of fetching one URL of a batch.
+type Result[T any] struct {
+	URL   string
+	Value T
+	Err   error
+}
+
+// GetMany fetches urls, through the client's cache if it has one, with at
+// most workers requests in flight. Results are in the order of urls and
+// carry their own error, so one failure does not hide the other results.
+// Once ctx is done, the URLs that were not started yet fail with ctx.Err().
+func (c *Client) GetMany(ctx context.Context, urls []string, workers int) []Result[[]byte] {
+	return Batch(ctx, urls, workers, c.Get, nil)
+}
+
+// ResolveMany is GetMany for references, decoding every resource into a T.
+func ResolveMany[T any](ctx context.Context, client *Client, refs []NamedAPIResource[T], workers int) []Result[T] {
+	urls := make([]string, len(refs))
+	for i, ref := range refs {
+		urls[i] = ref.URL
+	}
+	return Batch(ctx, urls, workers, func(ctx context.Context, url string) (T, error) {
+		return resolve[T](ctx, client, url)
+	}, nil)
+}
+
+// ResourceURLs returns the URLs of several resources of one endpoint, such as
+// ResourceURLs("pokemon", "pikachu", "eevee"), for use with GetMany.
+func (c *Client) ResourceURLs(resource string, names ...string) []string {
+	urls := make([]string, len(names))
+	for i, name := range names {
+		urls[i] = c.ResourceURL(resource, name)
+	}
+	return urls
+}
+
+// Batch is the worker pool behind GetMany, for callers that fetch urls with
+// something other than the client, such as their own cache. If progress is
+// not nil it is called after every URL with the number of URLs done so far,
+// never by two workers at once.
+func Batch[T any](ctx context.Context, urls []string, workers int, fetch func(ctx context.Context, url string) (T, error), progress func(done int)) []Result[T] {
+	if workers <= 0 {
+		workers = DefaultBatchWorkers
+	}
+	results := make([]Result[T], len(urls))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	finished := func() {
+		if progress == nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		progress(done)
+	}
+	for range min(workers, len(urls)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := &results[i]
+				result.URL = urls[i]
+				if err := ctx.Err(); err != nil {
+					result.Err = err
+				} else {
+					result.Value, result.Err = fetch(ctx, urls[i])
+				}
+				finished()
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
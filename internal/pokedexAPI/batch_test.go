@@ -0,0 +1,166 @@
+package pokedexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pokecache "github.com/kwekkwekpatu/gokedex/internal/pokecache"
+)
+
+func TestGetMany(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		name := path.Base(r.URL.Path)
+		if name == "missingno" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"name": %q}`, name)
+	}))
+	defer server.Close()
+	client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+
+	names := []string{"bulbasaur", "ivysaur", "missingno", "venusaur", "charmander", "charmeleon", "charizard"}
+	results := client.GetMany(context.Background(), client.ResourceURLs("pokemon", names...), 3)
+	if len(results) != len(names) {
+		t.Fatalf("expected %d results, got %d", len(names), len(results))
+	}
+	for i, result := range results {
+		if names[i] == "missingno" {
+			if !errors.Is(result.Err, ErrNotFound) {
+				t.Errorf("expected missingno to be not found, got %v", result.Err)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("%s: %v", names[i], result.Err)
+			continue
+		}
+		if expected := fmt.Sprintf(`{"name": %q}`, names[i]); string(result.Value) != expected {
+			t.Errorf("expected result %d to be %s, got %s", i, expected, result.Value)
+		}
+	}
+	if n := maxInFlight.Load(); n > 3 {
+		t.Errorf("expected at most 3 requests in flight, got %d", n)
+	}
+}
+
+func TestResolveMany(t *testing.T) {
+	client := NewClient(WithCache(&fixtureCache{}))
+	refs := []NamedAPIResource[Move]{
+		{Name: "thunderbolt", URL: "https://pokeapi.co/api/v2/move/85/"},
+		{Name: "thunder", URL: "https://pokeapi.co/api/v2/move/87/"},
+	}
+	for _, result := range ResolveMany(context.Background(), client, refs, 0) {
+		if result.Err != nil || result.Value.Name != "thunderbolt" {
+			t.Errorf("expected the move fixture for %s, got %+v", result.URL, result)
+		}
+	}
+}
+
+func TestGetManyCanceled(t *testing.T) {
+	var requests atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		cancel()
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+
+	results := client.GetMany(ctx, client.ResourceURLs("pokemon", "1", "2", "3", "4"), 1)
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected the batch to stop after the first request, got %d requests", n)
+	}
+	for _, result := range results[1:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected %s to be canceled, got %v", result.URL, result.Err)
+		}
+	}
+}
+
+func TestBatchProgress(t *testing.T) {
+	urls := []string{"a", "b", "c", "d", "e"}
+	var reported []int
+	results := Batch(context.Background(), urls, 3, func(ctx context.Context, url string) (string, error) {
+		if url == "c" {
+			return "", errors.New("failed")
+		}
+		return url, nil
+	}, func(done int) {
+		reported = append(reported, done)
+	})
+	if !slices.Equal(reported, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("expected progress after every URL, got %v", reported)
+	}
+	for i, result := range results {
+		if (result.Err != nil) != (urls[i] == "c") || result.Err == nil && result.Value != urls[i] {
+			t.Errorf("unexpected result for %s: %+v", urls[i], result)
+		}
+	}
+}
+
+// memoryCache reads through a pokecache.MemoryCache and fetches its misses
+// with GetConditional, the way gokedex wires up its client.
+type memoryCache struct {
+	client *Client
+	cache  *pokecache.MemoryCache
+}
+
+func (c *memoryCache) Get(ctx context.Context, url string) ([]byte, error) {
+	return c.cache.GetOrFetch(ctx, url, time.Minute, func(ctx context.Context, stale pokecache.Validators) (pokecache.Response, error) {
+		response, err := c.client.GetConditional(ctx, url, stale.ETag, stale.LastModified)
+		if err != nil {
+			return pokecache.Response{}, err
+		}
+		return pokecache.Response{Val: response.Body}, nil
+	})
+}
+
+func TestGetManyCanceledThroughCache(t *testing.T) {
+	arrived := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+	cache := &memoryCache{cache: pokecache.NewMemoryCache(time.Minute)}
+	defer cache.cache.Close()
+	client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}), WithCache(cache))
+	cache.client = client
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-arrived
+		<-arrived
+		cancel()
+	}()
+	for _, result := range client.GetMany(ctx, client.ResourceURLs("pokemon", "1", "2"), 2) {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("expected the request for %s to be canceled while in flight, got %v", result.URL, result.Err)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package pokedexapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the root of the public PokeAPI.
+const DefaultBaseURL = "https://pokeapi.co/api/v2/"
+
+// DefaultTimeout bounds a single request unless the client is configured
+// otherwise or the caller's context expires first.
+const DefaultTimeout = 10 * time.Second
+
+// DefaultUserAgent identifies gokedex to PokeAPI.
+const DefaultUserAgent = "gokedex"
+
+// Client talks to a PokeAPI instance, either the public one or a mirror or
+// stub server at another base URL. A Client is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	timeout    time.Duration
+	retry      RetryPolicy
+	limiter    *RateLimiter
+	debug      io.Writer
+	cache      Cache
+	strict     bool
+}
+
+// Cache lets Get, and with it Resolve and the resource methods, answer from
+// cached responses. A cache fetches its misses with GetConditional, which
+// always goes to the network. Errors must be this package's typed errors,
+// such as NotFoundError for a resource that does not exist, including when
+// the cache answers without asking PokeAPI, so that callers see the same
+// errors with and without a cache.
+type Cache interface {
+	Get(ctx context.Context, url string) ([]byte, error)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBaseURL points the client at another PokeAPI instance, for example
+// "http://localhost:8000/api/v2/".
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/") + "/"
+	}
+}
+
+// WithHTTPClient sends requests through httpClient instead of
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithTimeout bounds every attempt of a request to d. A zero d leaves
+// requests bounded only by their context.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithRateLimiter holds every request, including retries, until limiter
+// allows it. Share one limiter between clients to share the budget.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithDebug writes a line to w whenever a request is throttled or retried.
+func WithDebug(w io.Writer) Option {
+	return func(c *Client) {
+		c.debug = w
+	}
+}
+
+// WithCache reads every Get through cache.
+func WithCache(cache Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+func NewClient(opts ...Option) *Client {
+	client := &Client{
+		baseURL:    DefaultBaseURL,
+		httpClient: http.DefaultClient,
+		userAgent:  DefaultUserAgent,
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// DefaultClient is used by the package level Get and GetConditional.
+var DefaultClient = NewClient()
+
+// BaseURL returns the base URL of the client, always with a trailing slash.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// ResourceURL returns the URL of an endpoint below the base URL, such as
+// ResourceURL("pokemon", "pikachu"). Path segments are escaped.
+func (c *Client) ResourceURL(resource string, path ...string) string {
+	segments := []string{resource}
+	for _, segment := range path {
+		segments = append(segments, url.PathEscape(segment))
+	}
+	return c.baseURL + strings.Join(segments, "/")
+}
+
+// Get returns the body at url, from the client's cache if it has one.
+func (c *Client) Get(ctx context.Context, url string) ([]byte, error) {
+	if c.cache != nil {
+		return c.cache.Get(ctx, url)
+	}
+	response, err := c.GetConditional(ctx, url, "", "")
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// GetConditional sends If-None-Match and If-Modified-Since for the non-empty
+// validators so the server can answer 304 when the cached copy is current.
+// Failed attempts are retried according to the client's RetryPolicy.
+func (c *Client) GetConditional(ctx context.Context, url, etag, lastModified string) (Response, error) {
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			waited, err := c.limiter.Wait(ctx)
+			if err != nil {
+				return Response{}, err
+			}
+			if waited > 0 {
+				c.debugf("throttled %s for %s", url, waited.Round(time.Millisecond))
+			}
+		}
+		response, err := c.getOnce(ctx, url, etag, lastModified)
+		if err == nil {
+			return response, nil
+		}
+		delay, retry := c.retry.retryDelay(ctx, err, attempt)
+		if !retry {
+			return response, err
+		}
+		c.debugf("attempt %d failed: %v, retrying in %s", attempt, err, delay.Round(time.Millisecond))
+		if err := sleep(ctx, delay); err != nil {
+			return Response{}, err
+		}
+	}
+}
+
+func (c *Client) debugf(format string, args ...any) {
+	if c.debug != nil {
+		fmt.Fprintf(c.debug, "pokedexapi: "+format+"\n", args...)
+	}
+}
+
+func (c *Client) getOnce(ctx context.Context, url, etag, lastModified string) (Response, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Response{}, err
+	}
+	if c.userAgent != "" {
+		request.Header.Set("User-Agent", c.userAgent)
+	}
+	if etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		request.Header.Set("If-Modified-Since", lastModified)
+	}
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return Response{}, err
+	}
+	defer response.Body.Close()
+
+	result := Response{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}
+	if response.StatusCode == http.StatusNotModified {
+		result.NotModified = true
+		return result, nil
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		// Drain the body so the connection can be reused.
+		io.Copy(io.Discard, response.Body)
+		return result, statusError(url, response, time.Now())
+	}
+	// The body is read while the timeout still applies.
+	result.Body, err = io.ReadAll(response.Body)
+	return result, err
+}
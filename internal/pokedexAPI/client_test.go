@@ -0,0 +1,151 @@
+package pokedexapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/pokemon/mr-mime" {
+			http.NotFound(w, r)
+			return
+		}
+		if agent := r.Header.Get("User-Agent"); agent != "gokedex-test" {
+			t.Errorf("expected user agent gokedex-test, got %q", agent)
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"id": 122, "name": "mr-mime"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL+"/api/v2"), WithUserAgent("gokedex-test"))
+	url := client.ResourceURL("pokemon", "mr-mime")
+	if expected := server.URL + "/api/v2/pokemon/mr-mime"; url != expected {
+		t.Fatalf("expected %s, got %s", expected, url)
+	}
+
+	response, err := client.GetConditional(context.Background(), url, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ETag != `"v1"` || string(response.Body) != `{"id": 122, "name": "mr-mime"}` {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	response, err = client.GetConditional(context.Background(), url, `"v1"`, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.NotModified {
+		t.Errorf("expected a not modified response, got %+v", response)
+	}
+
+	_, err = client.Get(context.Background(), client.ResourceURL("pokemon", "missingno"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(WithBaseURL(server.URL), WithTimeout(10*time.Millisecond), WithRetry(RetryPolicy{}))
+	_, err := client.Get(context.Background(), client.ResourceURL("pokemon", "1"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the request to time out, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client = NewClient(WithBaseURL(server.URL), WithTimeout(0))
+	_, err = client.Get(ctx, client.ResourceURL("pokemon", "1"))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the request to be canceled, got %v", err)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/busy":
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("<html>maintenance</html>"))
+		case "/teapot":
+			w.WriteHeader(http.StatusTeapot)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+	ctx := context.Background()
+
+	_, err := client.Get(ctx, client.ResourceURL("busy"))
+	var rateLimited *RateLimitedError
+	if !errors.As(err, &rateLimited) || rateLimited.RetryAfter != 7*time.Second {
+		t.Errorf("expected a RateLimitedError retrying after 7s, got %v", err)
+	}
+
+	_, err = client.Get(ctx, client.ResourceURL("down"))
+	var serverError *ServerError
+	if !errors.As(err, &serverError) || serverError.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected a ServerError with status 503, got %v", err)
+	}
+
+	_, err = client.Get(ctx, client.ResourceURL("teapot"))
+	var statusError *StatusError
+	if !errors.As(err, &statusError) || statusError.StatusCode != http.StatusTeapot {
+		t.Errorf("expected a StatusError with status 418, got %v", err)
+	}
+
+	_, err = client.Get(ctx, client.ResourceURL("missing"))
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected a NotFoundError, got %v", err)
+	}
+
+	_, err = UnmarshalPokemon([]byte("<html>maintenance</html>"))
+	var decodeError *DecodeError
+	if !errors.As(err, &decodeError) || decodeError.Model != "Pokemon" {
+		t.Errorf("expected a DecodeError for Pokemon, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		header   string
+		expected time.Duration
+	}{
+		{header: "", expected: 0},
+		{header: "120", expected: 2 * time.Minute},
+		{header: "-5", expected: 0},
+		{header: "Mon, 01 Jan 2024 12:00:30 GMT", expected: 30 * time.Second},
+		{header: "soon", expected: 0},
+	}
+	for _, c := range cases {
+		if actual := retryAfter(c.header, now); actual != c.expected {
+			t.Errorf("retryAfter(%q) = %s, expected %s", c.header, actual, c.expected)
+		}
+	}
+}
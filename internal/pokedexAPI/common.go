@@ -0,0 +1,99 @@
+package pokedexapi
+
+import (
+	"context"
+	"fmt"
+)
+
+// Types shared by several PokeAPI resources.
+
+// NamedAPIResource refers to another resource of type T by name and URL.
+// References to resources gokedex has no model for use any, and resolve to
+// the raw decoded JSON.
+type NamedAPIResource[T any] struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Resolve fetches the referenced resource with client, through the client's
+// cache if it has one.
+func (r NamedAPIResource[T]) Resolve(ctx context.Context, client *Client) (T, error) {
+	return resolve[T](ctx, client, r.URL)
+}
+
+// APIResource refers to another resource of type T that has no name, such
+// as an evolution chain.
+type APIResource[T any] struct {
+	URL string `json:"url"`
+}
+
+// Resolve fetches the referenced resource with client, through the client's
+// cache if it has one.
+func (r APIResource[T]) Resolve(ctx context.Context, client *Client) (T, error) {
+	return resolve[T](ctx, client, r.URL)
+}
+
+func resolve[T any](ctx context.Context, client *Client, url string) (T, error) {
+	if url == "" {
+		var zero T
+		return zero, fmt.Errorf("cannot resolve an empty %s reference", modelName[T]())
+	}
+	body, err := client.Get(ctx, url)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Decoder[T](client)(body)
+}
+
+type Name struct {
+	Name     string                `json:"name"`
+	Language NamedAPIResource[any] `json:"language"`
+}
+
+type Description struct {
+	Description string                `json:"description"`
+	Language    NamedAPIResource[any] `json:"language"`
+}
+
+type Effect struct {
+	Effect   string                `json:"effect"`
+	Language NamedAPIResource[any] `json:"language"`
+}
+
+type VerboseEffect struct {
+	Effect      string                `json:"effect"`
+	ShortEffect string                `json:"short_effect"`
+	Language    NamedAPIResource[any] `json:"language"`
+}
+
+// FlavorText is the text shown in a game's pokedex for one version.
+type FlavorText struct {
+	FlavorText string                    `json:"flavor_text"`
+	Language   NamedAPIResource[any]     `json:"language"`
+	Version    NamedAPIResource[Version] `json:"version"`
+}
+
+// VersionGroupFlavorText is flavor text that applies to a whole version
+// group.
+type VersionGroupFlavorText struct {
+	FlavorText   string                         `json:"flavor_text"`
+	Language     NamedAPIResource[any]          `json:"language"`
+	VersionGroup NamedAPIResource[VersionGroup] `json:"version_group"`
+}
+
+type GenerationGameIndex struct {
+	GameIndex  int                          `json:"game_index"`
+	Generation NamedAPIResource[Generation] `json:"generation"`
+}
+
+// AbilityEffectChange records how an effect worked in earlier version groups.
+type AbilityEffectChange struct {
+	EffectEntries []Effect                       `json:"effect_entries"`
+	VersionGroup  NamedAPIResource[VersionGroup] `json:"version_group"`
+}
+
+type MachineVersionDetail struct {
+	Machine      APIResource[any]               `json:"machine"`
+	VersionGroup NamedAPIResource[VersionGroup] `json:"version_group"`
+}
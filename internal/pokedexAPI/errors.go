@@ -0,0 +1,114 @@
+package pokedexapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrNotFound is matched by every NotFoundError, so callers that only care
+// whether a resource exists can use errors.Is.
+var ErrNotFound = errors.New("resource not found")
+
+// NotFoundError is returned when PokeAPI answers 404 for a resource.
+type NotFoundError struct {
+	URL string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s: %v", e.URL, ErrNotFound)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// RateLimitedError is returned when PokeAPI answers 429. RetryAfter is the
+// delay the server asked for, or zero if it did not say.
+type RateLimitedError struct {
+	URL        string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s: rate limited, retry after %s", e.URL, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s: rate limited", e.URL)
+}
+
+// ServerError is returned for 5xx answers. RetryAfter is set if the server
+// said when it expects to be available again, as with a 503.
+type ServerError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("%s: server error: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// StatusError is returned for any other status code that is not a success.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: unexpected status: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// DecodeError is returned when a body cannot be decoded into Model, for
+// example because a proxy answered with an HTML page.
+type DecodeError struct {
+	Model string
+	Err   error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding %s: %v", e.Model, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// statusError maps a response that is neither a success nor 304 to one of
+// the typed errors.
+func statusError(url string, response *http.Response, now time.Time) error {
+	switch code := response.StatusCode; {
+	case code == http.StatusNotFound:
+		return &NotFoundError{URL: url}
+	case code == http.StatusTooManyRequests:
+		return &RateLimitedError{
+			URL:        url,
+			RetryAfter: retryAfter(response.Header.Get("Retry-After"), now),
+		}
+	case code >= 500:
+		return &ServerError{
+			URL:        url,
+			StatusCode: code,
+			RetryAfter: retryAfter(response.Header.Get("Retry-After"), now),
+		}
+	default:
+		return &StatusError{URL: url, StatusCode: code}
+	}
+}
+
+// retryAfter parses a Retry-After header, which holds either a number of
+// seconds or an HTTP date.
+func retryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
@@ -0,0 +1,119 @@
+package pokedexapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// NamedAPIResourceList is one page of a list endpoint such as pokemon, move,
+// item, type or location-area. Next and Previous are nil on the last and
+// first page. The list does not know what kind of resource it lists, so its
+// results resolve to the raw decoded JSON.
+type NamedAPIResourceList struct {
+	Count    int                     `json:"count"`
+	Next     *string                 `json:"next"`
+	Previous *string                 `json:"previous"`
+	Results  []NamedAPIResource[any] `json:"results"`
+}
+
+func UnmarshalList(body []byte) (NamedAPIResourceList, error) {
+	return Unmarshal[NamedAPIResourceList](body)
+}
+
+// DefaultPageSize is the page size PokeAPI uses when none is asked for.
+const DefaultPageSize = 20
+
+var (
+	ErrNoNextPage     = errors.New("already at the last page")
+	ErrNoPreviousPage = errors.New("already at the first page")
+)
+
+// PageFetcher loads the list page at url. Callers usually route it through a
+// cache, Client.Pager uses the client directly.
+type PageFetcher func(ctx context.Context, url string) (NamedAPIResourceList, error)
+
+// Pager walks a list endpoint page by page in either direction. A Pager is
+// not safe for concurrent use.
+type Pager struct {
+	endpoint string
+	pageSize int
+	fetch    PageFetcher
+
+	offset int
+	page   *NamedAPIResourceList
+}
+
+// NewPager pages through the list at endpoint, such as
+// client.ResourceURL("pokemon"), pageSize entries at a time.
+func NewPager(endpoint string, pageSize int, fetch PageFetcher) *Pager {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	return &Pager{endpoint: endpoint, pageSize: pageSize, fetch: fetch}
+}
+
+// Pager pages through resource with requests sent by the client itself.
+func (c *Client) Pager(resource string, pageSize int) *Pager {
+	return NewPager(c.ResourceURL(resource), pageSize, func(ctx context.Context, url string) (NamedAPIResourceList, error) {
+		body, err := c.Get(ctx, url)
+		if err != nil {
+			return NamedAPIResourceList{}, err
+		}
+		return Decoder[NamedAPIResourceList](c)(body)
+	})
+}
+
+// Next loads the page after the current one, or the first page if none has
+// been loaded yet. It returns ErrNoNextPage after the last page.
+func (p *Pager) Next(ctx context.Context) (NamedAPIResourceList, error) {
+	if p.page == nil {
+		return p.Seek(ctx, 0)
+	}
+	if p.page.Next == nil || p.offset+p.pageSize >= p.page.Count {
+		return NamedAPIResourceList{}, ErrNoNextPage
+	}
+	return p.Seek(ctx, p.offset+p.pageSize)
+}
+
+// Previous loads the page before the current one. It returns
+// ErrNoPreviousPage on the first page or if no page has been loaded yet.
+func (p *Pager) Previous(ctx context.Context) (NamedAPIResourceList, error) {
+	if p.page == nil || p.offset == 0 {
+		return NamedAPIResourceList{}, ErrNoPreviousPage
+	}
+	return p.Seek(ctx, max(p.offset-p.pageSize, 0))
+}
+
+// Seek loads the page starting at offset. Next and Previous continue from
+// there.
+func (p *Pager) Seek(ctx context.Context, offset int) (NamedAPIResourceList, error) {
+	if offset < 0 {
+		return NamedAPIResourceList{}, fmt.Errorf("negative offset %d", offset)
+	}
+	page, err := p.fetch(ctx, p.URL(offset))
+	if err != nil {
+		return NamedAPIResourceList{}, err
+	}
+	p.offset = offset
+	p.page = &page
+	return page, nil
+}
+
+// URL returns the URL of the page starting at offset.
+func (p *Pager) URL(offset int) string {
+	return fmt.Sprintf("%s?offset=%d&limit=%d", p.endpoint, offset, p.pageSize)
+}
+
+// Offset returns the offset of the current page.
+func (p *Pager) Offset() int {
+	return p.offset
+}
+
+// Page returns the current page, if one has been loaded.
+func (p *Pager) Page() (NamedAPIResourceList, bool) {
+	if p.page == nil {
+		return NamedAPIResourceList{}, false
+	}
+	return *p.page, true
+}
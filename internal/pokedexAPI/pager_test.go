@@ -0,0 +1,130 @@
+package pokedexapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// listServer serves a list endpoint of count resources named item-0 and up,
+// honouring offset and limit like PokeAPI does.
+func listServer(t *testing.T, count int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		list := NamedAPIResourceList{Count: count}
+		for i := offset; i < min(offset+limit, count); i++ {
+			list.Results = append(list.Results, NamedAPIResource[any]{
+				Name: fmt.Sprintf("item-%d", i),
+				URL:  fmt.Sprintf("%s/item/%d/", server.URL, i),
+			})
+		}
+		if offset+limit < count {
+			next := fmt.Sprintf("%s/item?offset=%d&limit=%d", server.URL, offset+limit, limit)
+			list.Next = &next
+		}
+		if offset > 0 {
+			previous := fmt.Sprintf("%s/item?offset=%d&limit=%d", server.URL, max(offset-limit, 0), limit)
+			list.Previous = &previous
+		}
+		json.NewEncoder(w).Encode(list)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPager(t *testing.T) {
+	server := listServer(t, 45)
+	client := NewClient(WithBaseURL(server.URL))
+	pager := client.Pager("item", 20)
+	ctx := context.Background()
+
+	if _, err := pager.Previous(ctx); !errors.Is(err, ErrNoPreviousPage) {
+		t.Errorf("expected ErrNoPreviousPage before the first page, got %v", err)
+	}
+
+	var names []string
+	for {
+		page, err := pager.Next(ctx)
+		if errors.Is(err, ErrNoNextPage) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, result := range page.Results {
+			names = append(names, result.Name)
+		}
+	}
+	if len(names) != 45 || names[0] != "item-0" || names[44] != "item-44" {
+		t.Fatalf("expected to walk all 45 items, got %v", names)
+	}
+	if pager.Offset() != 40 {
+		t.Errorf("expected to end on offset 40, got %d", pager.Offset())
+	}
+
+	page, err := pager.Previous(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pager.Offset() != 20 || page.Results[0].Name != "item-20" {
+		t.Errorf("expected to go back to offset 20, got %d", pager.Offset())
+	}
+
+	page, err = pager.Seek(ctx, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Results[0].Name != "item-5" || len(page.Results) != 20 {
+		t.Errorf("expected a page starting at item-5, got %v", page.Results)
+	}
+	page, err = pager.Previous(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pager.Offset() != 0 || page.Results[0].Name != "item-0" {
+		t.Errorf("expected Previous to stop at offset 0, got %d", pager.Offset())
+	}
+}
+
+func TestPagerUsesFetcher(t *testing.T) {
+	var urls []string
+	pager := NewPager("https://example.com/api/v2/move", 0, func(ctx context.Context, url string) (NamedAPIResourceList, error) {
+		urls = append(urls, url)
+		return NamedAPIResourceList{Count: 1}, nil
+	})
+	if _, err := pager.Next(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pager.Next(context.Background()); !errors.Is(err, ErrNoNextPage) {
+		t.Errorf("expected ErrNoNextPage, got %v", err)
+	}
+	expected := "https://example.com/api/v2/move?offset=0&limit=20"
+	if len(urls) != 1 || urls[0] != expected {
+		t.Errorf("expected a single request for %s, got %v", expected, urls)
+	}
+}
+
+func TestPagerStrictDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"count": 0, "next": null, "previous": null, "results": [], "total": 0}`))
+	}))
+	defer server.Close()
+
+	lenient := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+	if _, err := lenient.Pager("pokemon", 0).Next(context.Background()); err != nil {
+		t.Fatalf("expected the unknown field to be ignored, got %v", err)
+	}
+	strict := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}), WithStrictDecoding())
+	_, err := strict.Pager("pokemon", 0).Next(context.Background())
+	if !errors.As(err, new(*SchemaError)) {
+		t.Errorf("expected a strict client's pager to reject the unknown field, got %v", err)
+	}
+}
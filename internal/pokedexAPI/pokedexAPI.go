@@ -1,393 +1,319 @@
 package pokedexapi
 
 import (
+	"context"
 	"encoding/json"
-	"io"
-	"net/http"
 )
 
-// var baseUrl string = "https://pokeapi.co/api/v2/"
-
-type LocationsResponse struct {
-	Count    int    `json:"count"`
-	Next     string `json:"next"`
-	Previous any    `json:"previous"`
-	Results  []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"results"`
-}
+// LocationsResponse is a page of the location-area listing.
+type LocationsResponse = NamedAPIResourceList
 
 type SpecificLocationResponse struct {
 	EncounterMethodRates []struct {
-		EncounterMethod struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"encounter_method"`
-		VersionDetails []struct {
-			Rate    int `json:"rate"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+		EncounterMethod NamedAPIResource[any] `json:"encounter_method"`
+		VersionDetails  []struct {
+			Rate    int                       `json:"rate"`
+			Version NamedAPIResource[Version] `json:"version"`
 		} `json:"version_details"`
 	} `json:"encounter_method_rates"`
-	GameIndex int `json:"game_index"`
-	ID        int `json:"id"`
-	Location  struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"location"`
-	Name  string `json:"name"`
-	Names []struct {
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-		Name string `json:"name"`
+	GameIndex int                        `json:"game_index"`
+	ID        int                        `json:"id"`
+	Location  NamedAPIResource[Location] `json:"location"`
+	Name      string                     `json:"name"`
+	Names     []struct {
+		Language NamedAPIResource[any] `json:"language"`
+		Name     string                `json:"name"`
 	} `json:"names"`
 	PokemonEncounters []struct {
-		Pokemon struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"pokemon"`
+		Pokemon        NamedAPIResource[Pokemon] `json:"pokemon"`
 		VersionDetails []struct {
 			EncounterDetails []struct {
-				Chance          int   `json:"chance"`
-				ConditionValues []any `json:"condition_values"`
-				MaxLevel        int   `json:"max_level"`
-				Method          struct {
-					Name string `json:"name"`
-					URL  string `json:"url"`
-				} `json:"method"`
-				MinLevel int `json:"min_level"`
+				Chance          int                     `json:"chance"`
+				ConditionValues []NamedAPIResource[any] `json:"condition_values"`
+				MaxLevel        int                     `json:"max_level"`
+				Method          NamedAPIResource[any]   `json:"method"`
+				MinLevel        int                     `json:"min_level"`
 			} `json:"encounter_details"`
-			MaxChance int `json:"max_chance"`
-			Version   struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+			MaxChance int                       `json:"max_chance"`
+			Version   NamedAPIResource[Version] `json:"version"`
 		} `json:"version_details"`
 	} `json:"pokemon_encounters"`
 }
 
 type Pokemon struct {
 	Abilities []struct {
-		Ability struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"ability"`
-		IsHidden bool `json:"is_hidden"`
-		Slot     int  `json:"slot"`
+		Ability  NamedAPIResource[Ability] `json:"ability"`
+		IsHidden bool                      `json:"is_hidden"`
+		Slot     int                       `json:"slot"`
 	} `json:"abilities"`
 	BaseExperience int `json:"base_experience"`
 	Cries          struct {
-		Latest string `json:"latest"`
-		Legacy string `json:"legacy"`
+		Latest string  `json:"latest"`
+		Legacy *string `json:"legacy"`
 	} `json:"cries"`
-	Forms []struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"forms"`
+	Forms       []NamedAPIResource[any] `json:"forms"`
 	GameIndices []struct {
-		GameIndex int `json:"game_index"`
-		Version   struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"version"`
+		GameIndex int                       `json:"game_index"`
+		Version   NamedAPIResource[Version] `json:"version"`
 	} `json:"game_indices"`
 	Height    int `json:"height"`
 	HeldItems []struct {
-		Item struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"item"`
+		Item           NamedAPIResource[Item] `json:"item"`
 		VersionDetails []struct {
-			Rarity  int `json:"rarity"`
-			Version struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version"`
+			Rarity  int                       `json:"rarity"`
+			Version NamedAPIResource[Version] `json:"version"`
 		} `json:"version_details"`
 	} `json:"held_items"`
 	ID                     int    `json:"id"`
 	IsDefault              bool   `json:"is_default"`
 	LocationAreaEncounters string `json:"location_area_encounters"`
 	Moves                  []struct {
-		Move struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"move"`
+		Move                NamedAPIResource[Move] `json:"move"`
 		VersionGroupDetails []struct {
-			LevelLearnedAt  int `json:"level_learned_at"`
-			MoveLearnMethod struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"move_learn_method"`
-			VersionGroup struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"version_group"`
+			LevelLearnedAt  int                            `json:"level_learned_at"`
+			MoveLearnMethod NamedAPIResource[any]          `json:"move_learn_method"`
+			VersionGroup    NamedAPIResource[VersionGroup] `json:"version_group"`
 		} `json:"version_group_details"`
 	} `json:"moves"`
 	Name          string `json:"name"`
 	Order         int    `json:"order"`
 	PastAbilities []any  `json:"past_abilities"`
 	PastTypes     []struct {
-		Generation struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"generation"`
-		Types []struct {
-			Slot int `json:"slot"`
-			Type struct {
-				Name string `json:"name"`
-				URL  string `json:"url"`
-			} `json:"type"`
+		Generation NamedAPIResource[Generation] `json:"generation"`
+		Types      []struct {
+			Slot int                    `json:"slot"`
+			Type NamedAPIResource[Type] `json:"type"`
 		} `json:"types"`
 	} `json:"past_types"`
-	Species struct {
-		Name string `json:"name"`
-		URL  string `json:"url"`
-	} `json:"species"`
+	Species NamedAPIResource[PokemonSpecies] `json:"species"`
 	Sprites struct {
-		BackDefault      string `json:"back_default"`
-		BackFemale       any    `json:"back_female"`
-		BackShiny        string `json:"back_shiny"`
-		BackShinyFemale  any    `json:"back_shiny_female"`
-		FrontDefault     string `json:"front_default"`
-		FrontFemale      any    `json:"front_female"`
-		FrontShiny       string `json:"front_shiny"`
-		FrontShinyFemale any    `json:"front_shiny_female"`
+		BackDefault      *string `json:"back_default"`
+		BackFemale       any     `json:"back_female"`
+		BackShiny        *string `json:"back_shiny"`
+		BackShinyFemale  any     `json:"back_shiny_female"`
+		FrontDefault     *string `json:"front_default"`
+		FrontFemale      any     `json:"front_female"`
+		FrontShiny       *string `json:"front_shiny"`
+		FrontShinyFemale any     `json:"front_shiny_female"`
 		Other            struct {
 			DreamWorld struct {
-				FrontDefault string `json:"front_default"`
-				FrontFemale  any    `json:"front_female"`
+				FrontDefault *string `json:"front_default"`
+				FrontFemale  any     `json:"front_female"`
 			} `json:"dream_world"`
 			Home struct {
-				FrontDefault     string `json:"front_default"`
-				FrontFemale      any    `json:"front_female"`
-				FrontShiny       string `json:"front_shiny"`
-				FrontShinyFemale any    `json:"front_shiny_female"`
+				FrontDefault     *string `json:"front_default"`
+				FrontFemale      any     `json:"front_female"`
+				FrontShiny       *string `json:"front_shiny"`
+				FrontShinyFemale any     `json:"front_shiny_female"`
 			} `json:"home"`
 			OfficialArtwork struct {
-				FrontDefault string `json:"front_default"`
-				FrontShiny   string `json:"front_shiny"`
+				FrontDefault *string `json:"front_default"`
+				FrontShiny   *string `json:"front_shiny"`
 			} `json:"official-artwork"`
 			Showdown struct {
-				BackDefault      string `json:"back_default"`
-				BackFemale       any    `json:"back_female"`
-				BackShiny        string `json:"back_shiny"`
-				BackShinyFemale  any    `json:"back_shiny_female"`
-				FrontDefault     string `json:"front_default"`
-				FrontFemale      any    `json:"front_female"`
-				FrontShiny       string `json:"front_shiny"`
-				FrontShinyFemale any    `json:"front_shiny_female"`
+				BackDefault      *string `json:"back_default"`
+				BackFemale       any     `json:"back_female"`
+				BackShiny        *string `json:"back_shiny"`
+				BackShinyFemale  any     `json:"back_shiny_female"`
+				FrontDefault     *string `json:"front_default"`
+				FrontFemale      any     `json:"front_female"`
+				FrontShiny       *string `json:"front_shiny"`
+				FrontShinyFemale any     `json:"front_shiny_female"`
 			} `json:"showdown"`
 		} `json:"other"`
 		Versions struct {
 			GenerationI struct {
 				RedBlue struct {
-					BackDefault      string `json:"back_default"`
-					BackGray         string `json:"back_gray"`
-					BackTransparent  string `json:"back_transparent"`
-					FrontDefault     string `json:"front_default"`
-					FrontGray        string `json:"front_gray"`
-					FrontTransparent string `json:"front_transparent"`
+					BackDefault      *string `json:"back_default"`
+					BackGray         *string `json:"back_gray"`
+					BackTransparent  *string `json:"back_transparent"`
+					FrontDefault     *string `json:"front_default"`
+					FrontGray        *string `json:"front_gray"`
+					FrontTransparent *string `json:"front_transparent"`
 				} `json:"red-blue"`
 				Yellow struct {
-					BackDefault      string `json:"back_default"`
-					BackGray         string `json:"back_gray"`
-					BackTransparent  string `json:"back_transparent"`
-					FrontDefault     string `json:"front_default"`
-					FrontGray        string `json:"front_gray"`
-					FrontTransparent string `json:"front_transparent"`
+					BackDefault      *string `json:"back_default"`
+					BackGray         *string `json:"back_gray"`
+					BackTransparent  *string `json:"back_transparent"`
+					FrontDefault     *string `json:"front_default"`
+					FrontGray        *string `json:"front_gray"`
+					FrontTransparent *string `json:"front_transparent"`
 				} `json:"yellow"`
 			} `json:"generation-i"`
 			GenerationIi struct {
 				Crystal struct {
-					BackDefault           string `json:"back_default"`
-					BackShiny             string `json:"back_shiny"`
-					BackShinyTransparent  string `json:"back_shiny_transparent"`
-					BackTransparent       string `json:"back_transparent"`
-					FrontDefault          string `json:"front_default"`
-					FrontShiny            string `json:"front_shiny"`
-					FrontShinyTransparent string `json:"front_shiny_transparent"`
-					FrontTransparent      string `json:"front_transparent"`
+					BackDefault           *string `json:"back_default"`
+					BackShiny             *string `json:"back_shiny"`
+					BackShinyTransparent  *string `json:"back_shiny_transparent"`
+					BackTransparent       *string `json:"back_transparent"`
+					FrontDefault          *string `json:"front_default"`
+					FrontShiny            *string `json:"front_shiny"`
+					FrontShinyTransparent *string `json:"front_shiny_transparent"`
+					FrontTransparent      *string `json:"front_transparent"`
 				} `json:"crystal"`
 				Gold struct {
-					BackDefault      string `json:"back_default"`
-					BackShiny        string `json:"back_shiny"`
-					FrontDefault     string `json:"front_default"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontTransparent string `json:"front_transparent"`
+					BackDefault      *string `json:"back_default"`
+					BackShiny        *string `json:"back_shiny"`
+					FrontDefault     *string `json:"front_default"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontTransparent *string `json:"front_transparent"`
 				} `json:"gold"`
 				Silver struct {
-					BackDefault      string `json:"back_default"`
-					BackShiny        string `json:"back_shiny"`
-					FrontDefault     string `json:"front_default"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontTransparent string `json:"front_transparent"`
+					BackDefault      *string `json:"back_default"`
+					BackShiny        *string `json:"back_shiny"`
+					FrontDefault     *string `json:"front_default"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontTransparent *string `json:"front_transparent"`
 				} `json:"silver"`
 			} `json:"generation-ii"`
 			GenerationIii struct {
 				Emerald struct {
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
+					FrontDefault *string `json:"front_default"`
+					FrontShiny   *string `json:"front_shiny"`
 				} `json:"emerald"`
 				FireredLeafgreen struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
+					BackDefault  *string `json:"back_default"`
+					BackShiny    *string `json:"back_shiny"`
+					FrontDefault *string `json:"front_default"`
+					FrontShiny   *string `json:"front_shiny"`
 				} `json:"firered-leafgreen"`
 				RubySapphire struct {
-					BackDefault  string `json:"back_default"`
-					BackShiny    string `json:"back_shiny"`
-					FrontDefault string `json:"front_default"`
-					FrontShiny   string `json:"front_shiny"`
+					BackDefault  *string `json:"back_default"`
+					BackShiny    *string `json:"back_shiny"`
+					FrontDefault *string `json:"front_default"`
+					FrontShiny   *string `json:"front_shiny"`
 				} `json:"ruby-sapphire"`
 			} `json:"generation-iii"`
 			GenerationIv struct {
 				DiamondPearl struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					BackDefault      *string `json:"back_default"`
+					BackFemale       any     `json:"back_female"`
+					BackShiny        *string `json:"back_shiny"`
+					BackShinyFemale  any     `json:"back_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"diamond-pearl"`
 				HeartgoldSoulsilver struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					BackDefault      *string `json:"back_default"`
+					BackFemale       any     `json:"back_female"`
+					BackShiny        *string `json:"back_shiny"`
+					BackShinyFemale  any     `json:"back_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"heartgold-soulsilver"`
 				Platinum struct {
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					BackDefault      *string `json:"back_default"`
+					BackFemale       any     `json:"back_female"`
+					BackShiny        *string `json:"back_shiny"`
+					BackShinyFemale  any     `json:"back_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"platinum"`
 			} `json:"generation-iv"`
 			GenerationV struct {
 				BlackWhite struct {
 					Animated struct {
-						BackDefault      string `json:"back_default"`
-						BackFemale       any    `json:"back_female"`
-						BackShiny        string `json:"back_shiny"`
-						BackShinyFemale  any    `json:"back_shiny_female"`
-						FrontDefault     string `json:"front_default"`
-						FrontFemale      any    `json:"front_female"`
-						FrontShiny       string `json:"front_shiny"`
-						FrontShinyFemale any    `json:"front_shiny_female"`
+						BackDefault      *string `json:"back_default"`
+						BackFemale       any     `json:"back_female"`
+						BackShiny        *string `json:"back_shiny"`
+						BackShinyFemale  any     `json:"back_shiny_female"`
+						FrontDefault     *string `json:"front_default"`
+						FrontFemale      any     `json:"front_female"`
+						FrontShiny       *string `json:"front_shiny"`
+						FrontShinyFemale any     `json:"front_shiny_female"`
 					} `json:"animated"`
-					BackDefault      string `json:"back_default"`
-					BackFemale       any    `json:"back_female"`
-					BackShiny        string `json:"back_shiny"`
-					BackShinyFemale  any    `json:"back_shiny_female"`
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					BackDefault      *string `json:"back_default"`
+					BackFemale       any     `json:"back_female"`
+					BackShiny        *string `json:"back_shiny"`
+					BackShinyFemale  any     `json:"back_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"black-white"`
 			} `json:"generation-v"`
 			GenerationVi struct {
 				OmegarubyAlphasapphire struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"omegaruby-alphasapphire"`
 				XY struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"x-y"`
 			} `json:"generation-vi"`
 			GenerationVii struct {
 				Icons struct {
-					FrontDefault string `json:"front_default"`
-					FrontFemale  any    `json:"front_female"`
+					FrontDefault *string `json:"front_default"`
+					FrontFemale  any     `json:"front_female"`
 				} `json:"icons"`
 				UltraSunUltraMoon struct {
-					FrontDefault     string `json:"front_default"`
-					FrontFemale      any    `json:"front_female"`
-					FrontShiny       string `json:"front_shiny"`
-					FrontShinyFemale any    `json:"front_shiny_female"`
+					FrontDefault     *string `json:"front_default"`
+					FrontFemale      any     `json:"front_female"`
+					FrontShiny       *string `json:"front_shiny"`
+					FrontShinyFemale any     `json:"front_shiny_female"`
 				} `json:"ultra-sun-ultra-moon"`
 			} `json:"generation-vii"`
 			GenerationViii struct {
 				Icons struct {
-					FrontDefault string `json:"front_default"`
-					FrontFemale  any    `json:"front_female"`
+					FrontDefault *string `json:"front_default"`
+					FrontFemale  any     `json:"front_female"`
 				} `json:"icons"`
 			} `json:"generation-viii"`
 		} `json:"versions"`
 	} `json:"sprites"`
 	Stats []struct {
-		BaseStat int `json:"base_stat"`
-		Effort   int `json:"effort"`
-		Stat     struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"stat"`
+		BaseStat int                   `json:"base_stat"`
+		Effort   int                   `json:"effort"`
+		Stat     NamedAPIResource[any] `json:"stat"`
 	} `json:"stats"`
 	Types []struct {
-		Slot int `json:"slot"`
-		Type struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"type"`
+		Slot int                    `json:"slot"`
+		Type NamedAPIResource[Type] `json:"type"`
 	} `json:"types"`
 	Weight int `json:"weight"`
 }
 
-func Get(nextURL string) ([]byte, error) {
-	response, err := http.Get(nextURL)
-	if err != nil {
-		return nil, err
-	}
-	defer response.Body.Close()
+// Response is the body of a GET request together with its cache validators.
+// NotModified is set when a conditional request was answered with 304, in
+// which case Body is empty.
+type Response struct {
+	Body         []byte
+	ETag         string
+	LastModified string
+	NotModified  bool
+}
 
-	return io.ReadAll(response.Body)
+// Get downloads url with DefaultClient.
+func Get(url string) ([]byte, error) {
+	return DefaultClient.Get(context.Background(), url)
+}
 
+// GetConditional is Client.GetConditional on DefaultClient.
+func GetConditional(url, etag, lastModified string) (Response, error) {
+	return DefaultClient.GetConditional(context.Background(), url, etag, lastModified)
 }
 
 func UnmarshalLocations(body []byte) (LocationsResponse, error) {
-	locations := LocationsResponse{}
-	if err := json.Unmarshal(body, &locations); err != nil {
-		return locations, err
-	}
-
-	return locations, nil
+	return Unmarshal[LocationsResponse](body)
 }
 
 func UnmarshalLocation(body []byte) (SpecificLocationResponse, error) {
-	location := SpecificLocationResponse{}
-	if err := json.Unmarshal(body, &location); err != nil {
-		return location, err
-	}
-	return location, nil
+	return Unmarshal[SpecificLocationResponse](body)
 }
 
 func UnmarshalPokemon(body []byte) (Pokemon, error) {
-	pokemon := Pokemon{}
-	if err := json.Unmarshal(body, &pokemon); err != nil {
-		return pokemon, err
-	}
-	return pokemon, nil
+	return Unmarshal[Pokemon](body)
 }
 
 func UnmarshalBody(body []byte, v interface{}) (interface{}, error) {
@@ -0,0 +1,69 @@
+package pokedexapi
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that allows perSecond requests on average
+// and bursts of up to burst requests. It is safe for concurrent use, so one
+// limiter can be shared by every goroutine, and every client, that talks to
+// the same server.
+type RateLimiter struct {
+	mu        sync.Mutex
+	perSecond float64
+	burst     float64
+	tokens    float64
+	last      time.Time
+}
+
+// NewRateLimiter returns a limiter that starts with a full bucket.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	burst = max(burst, 1)
+	return &RateLimiter{
+		perSecond: perSecond,
+		burst:     float64(burst),
+		tokens:    float64(burst),
+		last:      time.Now(),
+	}
+}
+
+// Wait takes a token, blocking until one is available or ctx is done. It
+// reports how long the caller was held back.
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	delay := l.reserve(time.Now())
+	if delay <= 0 {
+		return 0, nil
+	}
+	if err := sleep(ctx, delay); err != nil {
+		l.cancel()
+		return 0, err
+	}
+	return delay, nil
+}
+
+// reserve takes a token, which may leave the bucket in debt, and returns how
+// long the caller has to wait until the token is actually available.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.perSecond <= 0 {
+		return 0
+	}
+	elapsed := now.Sub(l.last).Seconds()
+	l.last = now
+	l.tokens = min(l.tokens+elapsed*l.perSecond, l.burst)
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.perSecond * float64(time.Second))
+}
+
+// cancel returns a token that was reserved but not used.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens = min(l.tokens+1, l.burst)
+}
@@ -0,0 +1,87 @@
+package pokedexapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	limiter := NewRateLimiter(10, 2)
+	now := limiter.last
+
+	for i := range 2 {
+		if delay := limiter.reserve(now); delay != 0 {
+			t.Fatalf("expected request %d of the burst to pass, waited %s", i+1, delay)
+		}
+	}
+	if delay := limiter.reserve(now); delay != 100*time.Millisecond {
+		t.Errorf("expected to wait 100ms once the burst is used, got %s", delay)
+	}
+	if delay := limiter.reserve(now); delay != 200*time.Millisecond {
+		t.Errorf("expected waiting requests to queue up, got %s", delay)
+	}
+	// After a second the debt of two tokens is paid and the bucket is full.
+	now = now.Add(time.Second)
+	if delay := limiter.reserve(now); delay != 0 {
+		t.Errorf("expected the bucket to refill, waited %s", delay)
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := limiter.Wait(context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected 5 requests at 100/s to take about 40ms, took %s", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewRateLimiter(1, 1).Wait(ctx); err != nil {
+		t.Errorf("expected the burst to pass even with a canceled context, got %v", err)
+	}
+	limiter = NewRateLimiter(1, 1)
+	limiter.Wait(context.Background())
+	if _, err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a throttled wait to be canceled, got %v", err)
+	}
+}
+
+func TestClientRateLimitDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var debug bytes.Buffer
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithRateLimiter(NewRateLimiter(100, 1)),
+		WithDebug(&debug),
+	)
+	for range 2 {
+		if _, err := client.Get(context.Background(), client.ResourceURL("pokemon", "1")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !strings.Contains(debug.String(), "throttled") {
+		t.Errorf("expected the second request to be reported as throttled, got %q", debug.String())
+	}
+}
@@ -0,0 +1,439 @@
+package pokedexapi
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+)
+
+// Fields that PokeAPI sends as null are pointers, so that a missing value can
+// be told apart from a zero one.
+
+type PokemonSpecies struct {
+	ID                   int                               `json:"id"`
+	Name                 string                            `json:"name"`
+	Order                int                               `json:"order"`
+	GenderRate           int                               `json:"gender_rate"`
+	CaptureRate          int                               `json:"capture_rate"`
+	BaseHappiness        *int                              `json:"base_happiness"`
+	IsBaby               bool                              `json:"is_baby"`
+	IsLegendary          bool                              `json:"is_legendary"`
+	IsMythical           bool                              `json:"is_mythical"`
+	HatchCounter         *int                              `json:"hatch_counter"`
+	HasGenderDifferences bool                              `json:"has_gender_differences"`
+	FormsSwitchable      bool                              `json:"forms_switchable"`
+	GrowthRate           NamedAPIResource[any]             `json:"growth_rate"`
+	PokedexNumbers       []PokemonSpeciesDexEntry          `json:"pokedex_numbers"`
+	EggGroups            []NamedAPIResource[any]           `json:"egg_groups"`
+	Color                NamedAPIResource[any]             `json:"color"`
+	Shape                *NamedAPIResource[any]            `json:"shape"`
+	EvolvesFromSpecies   *NamedAPIResource[PokemonSpecies] `json:"evolves_from_species"`
+	EvolutionChain       APIResource[EvolutionChain]       `json:"evolution_chain"`
+	Habitat              *NamedAPIResource[any]            `json:"habitat"`
+	Generation           NamedAPIResource[Generation]      `json:"generation"`
+	Names                []Name                            `json:"names"`
+	PalParkEncounters    []PalParkEncounterArea            `json:"pal_park_encounters"`
+	FlavorTextEntries    []FlavorText                      `json:"flavor_text_entries"`
+	FormDescriptions     []Description                     `json:"form_descriptions"`
+	Genera               []Genus                           `json:"genera"`
+	Varieties            []PokemonSpeciesVariety           `json:"varieties"`
+}
+
+type PokemonSpeciesDexEntry struct {
+	EntryNumber int                   `json:"entry_number"`
+	Pokedex     NamedAPIResource[any] `json:"pokedex"`
+}
+
+type PalParkEncounterArea struct {
+	BaseScore int                   `json:"base_score"`
+	Rate      int                   `json:"rate"`
+	Area      NamedAPIResource[any] `json:"area"`
+}
+
+type Genus struct {
+	Genus    string                `json:"genus"`
+	Language NamedAPIResource[any] `json:"language"`
+}
+
+type PokemonSpeciesVariety struct {
+	IsDefault bool                      `json:"is_default"`
+	Pokemon   NamedAPIResource[Pokemon] `json:"pokemon"`
+}
+
+type EvolutionChain struct {
+	ID              int                     `json:"id"`
+	BabyTriggerItem *NamedAPIResource[Item] `json:"baby_trigger_item"`
+	Chain           ChainLink               `json:"chain"`
+}
+
+// ChainLink is one species in an evolution chain together with the species
+// it can evolve into.
+type ChainLink struct {
+	IsBaby           bool                             `json:"is_baby"`
+	Species          NamedAPIResource[PokemonSpecies] `json:"species"`
+	EvolutionDetails []EvolutionDetail                `json:"evolution_details"`
+	EvolvesTo        []ChainLink                      `json:"evolves_to"`
+}
+
+type EvolutionDetail struct {
+	Item                  *NamedAPIResource[Item]           `json:"item"`
+	Trigger               NamedAPIResource[any]             `json:"trigger"`
+	Gender                *int                              `json:"gender"`
+	HeldItem              *NamedAPIResource[Item]           `json:"held_item"`
+	KnownMove             *NamedAPIResource[Move]           `json:"known_move"`
+	KnownMoveType         *NamedAPIResource[Type]           `json:"known_move_type"`
+	Location              *NamedAPIResource[Location]       `json:"location"`
+	MinLevel              *int                              `json:"min_level"`
+	MinHappiness          *int                              `json:"min_happiness"`
+	MinBeauty             *int                              `json:"min_beauty"`
+	MinAffection          *int                              `json:"min_affection"`
+	NeedsOverworldRain    bool                              `json:"needs_overworld_rain"`
+	PartySpecies          *NamedAPIResource[PokemonSpecies] `json:"party_species"`
+	PartyType             *NamedAPIResource[Type]           `json:"party_type"`
+	RelativePhysicalStats *int                              `json:"relative_physical_stats"`
+	TimeOfDay             string                            `json:"time_of_day"`
+	TradeSpecies          *NamedAPIResource[PokemonSpecies] `json:"trade_species"`
+	TurnUpsideDown        bool                              `json:"turn_upside_down"`
+}
+
+type Move struct {
+	ID                 int                          `json:"id"`
+	Name               string                       `json:"name"`
+	Accuracy           *int                         `json:"accuracy"`
+	EffectChance       *int                         `json:"effect_chance"`
+	PP                 *int                         `json:"pp"`
+	Priority           int                          `json:"priority"`
+	Power              *int                         `json:"power"`
+	ContestCombos      *ContestComboSets            `json:"contest_combos"`
+	ContestType        *NamedAPIResource[any]       `json:"contest_type"`
+	ContestEffect      *APIResource[any]            `json:"contest_effect"`
+	DamageClass        NamedAPIResource[any]        `json:"damage_class"`
+	EffectEntries      []VerboseEffect              `json:"effect_entries"`
+	EffectChanges      []AbilityEffectChange        `json:"effect_changes"`
+	LearnedByPokemon   []NamedAPIResource[Pokemon]  `json:"learned_by_pokemon"`
+	FlavorTextEntries  []VersionGroupFlavorText     `json:"flavor_text_entries"`
+	Generation         NamedAPIResource[Generation] `json:"generation"`
+	Machines           []MachineVersionDetail       `json:"machines"`
+	Meta               *MoveMetaData                `json:"meta"`
+	Names              []Name                       `json:"names"`
+	PastValues         []PastMoveStatValues         `json:"past_values"`
+	StatChanges        []MoveStatChange             `json:"stat_changes"`
+	SuperContestEffect *APIResource[any]            `json:"super_contest_effect"`
+	Target             NamedAPIResource[any]        `json:"target"`
+	Type               NamedAPIResource[Type]       `json:"type"`
+}
+
+type ContestComboSets struct {
+	Normal ContestComboDetail `json:"normal"`
+	Super  ContestComboDetail `json:"super"`
+}
+
+type ContestComboDetail struct {
+	UseBefore []NamedAPIResource[Move] `json:"use_before"`
+	UseAfter  []NamedAPIResource[Move] `json:"use_after"`
+}
+
+type MoveMetaData struct {
+	Ailment       NamedAPIResource[any] `json:"ailment"`
+	Category      NamedAPIResource[any] `json:"category"`
+	MinHits       *int                  `json:"min_hits"`
+	MaxHits       *int                  `json:"max_hits"`
+	MinTurns      *int                  `json:"min_turns"`
+	MaxTurns      *int                  `json:"max_turns"`
+	Drain         int                   `json:"drain"`
+	Healing       int                   `json:"healing"`
+	CritRate      int                   `json:"crit_rate"`
+	AilmentChance int                   `json:"ailment_chance"`
+	FlinchChance  int                   `json:"flinch_chance"`
+	StatChance    int                   `json:"stat_chance"`
+}
+
+type MoveStatChange struct {
+	Change int                   `json:"change"`
+	Stat   NamedAPIResource[any] `json:"stat"`
+}
+
+// PastMoveStatValues holds the values a move had before VersionGroup
+// changed them.
+type PastMoveStatValues struct {
+	Accuracy      *int                           `json:"accuracy"`
+	EffectChance  *int                           `json:"effect_chance"`
+	Power         *int                           `json:"power"`
+	PP            *int                           `json:"pp"`
+	EffectEntries []VerboseEffect                `json:"effect_entries"`
+	Type          *NamedAPIResource[Type]        `json:"type"`
+	VersionGroup  NamedAPIResource[VersionGroup] `json:"version_group"`
+}
+
+type Ability struct {
+	ID                int                          `json:"id"`
+	Name              string                       `json:"name"`
+	IsMainSeries      bool                         `json:"is_main_series"`
+	Generation        NamedAPIResource[Generation] `json:"generation"`
+	Names             []Name                       `json:"names"`
+	EffectEntries     []VerboseEffect              `json:"effect_entries"`
+	EffectChanges     []AbilityEffectChange        `json:"effect_changes"`
+	FlavorTextEntries []VersionGroupFlavorText     `json:"flavor_text_entries"`
+	Pokemon           []AbilityPokemon             `json:"pokemon"`
+}
+
+type AbilityPokemon struct {
+	IsHidden bool                      `json:"is_hidden"`
+	Slot     int                       `json:"slot"`
+	Pokemon  NamedAPIResource[Pokemon] `json:"pokemon"`
+}
+
+type Type struct {
+	ID                  int                          `json:"id"`
+	Name                string                       `json:"name"`
+	DamageRelations     TypeRelations                `json:"damage_relations"`
+	PastDamageRelations []TypeRelationsPast          `json:"past_damage_relations"`
+	GameIndices         []GenerationGameIndex        `json:"game_indices"`
+	Generation          NamedAPIResource[Generation] `json:"generation"`
+	MoveDamageClass     *NamedAPIResource[any]       `json:"move_damage_class"`
+	Names               []Name                       `json:"names"`
+	Pokemon             []TypePokemon                `json:"pokemon"`
+	Moves               []NamedAPIResource[Move]     `json:"moves"`
+}
+
+type TypeRelations struct {
+	NoDamageTo       []NamedAPIResource[Type] `json:"no_damage_to"`
+	HalfDamageTo     []NamedAPIResource[Type] `json:"half_damage_to"`
+	DoubleDamageTo   []NamedAPIResource[Type] `json:"double_damage_to"`
+	NoDamageFrom     []NamedAPIResource[Type] `json:"no_damage_from"`
+	HalfDamageFrom   []NamedAPIResource[Type] `json:"half_damage_from"`
+	DoubleDamageFrom []NamedAPIResource[Type] `json:"double_damage_from"`
+}
+
+// TypeRelationsPast holds the damage relations a type had up to Generation.
+type TypeRelationsPast struct {
+	Generation      NamedAPIResource[Generation] `json:"generation"`
+	DamageRelations TypeRelations                `json:"damage_relations"`
+}
+
+type TypePokemon struct {
+	Slot    int                       `json:"slot"`
+	Pokemon NamedAPIResource[Pokemon] `json:"pokemon"`
+}
+
+type Item struct {
+	ID                int                          `json:"id"`
+	Name              string                       `json:"name"`
+	Cost              int                          `json:"cost"`
+	FlingPower        *int                         `json:"fling_power"`
+	FlingEffect       *NamedAPIResource[any]       `json:"fling_effect"`
+	Attributes        []NamedAPIResource[any]      `json:"attributes"`
+	Category          NamedAPIResource[any]        `json:"category"`
+	EffectEntries     []VerboseEffect              `json:"effect_entries"`
+	FlavorTextEntries []ItemFlavorText             `json:"flavor_text_entries"`
+	GameIndices       []GenerationGameIndex        `json:"game_indices"`
+	Names             []Name                       `json:"names"`
+	Sprites           ItemSprites                  `json:"sprites"`
+	HeldByPokemon     []ItemHolderPokemon          `json:"held_by_pokemon"`
+	BabyTriggerFor    *APIResource[EvolutionChain] `json:"baby_trigger_for"`
+	Machines          []MachineVersionDetail       `json:"machines"`
+}
+
+// ItemFlavorText is like VersionGroupFlavorText, but PokeAPI calls the text
+// field "text" for items.
+type ItemFlavorText struct {
+	Text         string                         `json:"text"`
+	Language     NamedAPIResource[any]          `json:"language"`
+	VersionGroup NamedAPIResource[VersionGroup] `json:"version_group"`
+}
+
+type ItemSprites struct {
+	Default *string `json:"default"`
+}
+
+type ItemHolderPokemon struct {
+	Pokemon        NamedAPIResource[Pokemon]        `json:"pokemon"`
+	VersionDetails []ItemHolderPokemonVersionDetail `json:"version_details"`
+}
+
+type ItemHolderPokemonVersionDetail struct {
+	Rarity  int                       `json:"rarity"`
+	Version NamedAPIResource[Version] `json:"version"`
+}
+
+type Berry struct {
+	ID               int                    `json:"id"`
+	Name             string                 `json:"name"`
+	GrowthTime       int                    `json:"growth_time"`
+	MaxHarvest       int                    `json:"max_harvest"`
+	NaturalGiftPower int                    `json:"natural_gift_power"`
+	Size             int                    `json:"size"`
+	Smoothness       int                    `json:"smoothness"`
+	SoilDryness      int                    `json:"soil_dryness"`
+	Firmness         NamedAPIResource[any]  `json:"firmness"`
+	Flavors          []BerryFlavorMap       `json:"flavors"`
+	Item             NamedAPIResource[Item] `json:"item"`
+	NaturalGiftType  NamedAPIResource[Type] `json:"natural_gift_type"`
+}
+
+type BerryFlavorMap struct {
+	Potency int                   `json:"potency"`
+	Flavor  NamedAPIResource[any] `json:"flavor"`
+}
+
+type Nature struct {
+	ID                         int                         `json:"id"`
+	Name                       string                      `json:"name"`
+	DecreasedStat              *NamedAPIResource[any]      `json:"decreased_stat"`
+	IncreasedStat              *NamedAPIResource[any]      `json:"increased_stat"`
+	HatesFlavor                *NamedAPIResource[any]      `json:"hates_flavor"`
+	LikesFlavor                *NamedAPIResource[any]      `json:"likes_flavor"`
+	PokeathlonStatChanges      []NatureStatChange          `json:"pokeathlon_stat_changes"`
+	MoveBattleStylePreferences []MoveBattleStylePreference `json:"move_battle_style_preferences"`
+	Names                      []Name                      `json:"names"`
+}
+
+type NatureStatChange struct {
+	MaxChange      int                   `json:"max_change"`
+	PokeathlonStat NamedAPIResource[any] `json:"pokeathlon_stat"`
+}
+
+type MoveBattleStylePreference struct {
+	LowHPPreference  int                   `json:"low_hp_preference"`
+	HighHPPreference int                   `json:"high_hp_preference"`
+	MoveBattleStyle  NamedAPIResource[any] `json:"move_battle_style"`
+}
+
+type Generation struct {
+	ID             int                                `json:"id"`
+	Name           string                             `json:"name"`
+	Abilities      []NamedAPIResource[Ability]        `json:"abilities"`
+	Names          []Name                             `json:"names"`
+	MainRegion     NamedAPIResource[Region]           `json:"main_region"`
+	Moves          []NamedAPIResource[Move]           `json:"moves"`
+	PokemonSpecies []NamedAPIResource[PokemonSpecies] `json:"pokemon_species"`
+	Types          []NamedAPIResource[Type]           `json:"types"`
+	VersionGroups  []NamedAPIResource[VersionGroup]   `json:"version_groups"`
+}
+
+type Region struct {
+	ID             int                              `json:"id"`
+	Name           string                           `json:"name"`
+	Locations      []NamedAPIResource[Location]     `json:"locations"`
+	MainGeneration *NamedAPIResource[Generation]    `json:"main_generation"`
+	Names          []Name                           `json:"names"`
+	Pokedexes      []NamedAPIResource[any]          `json:"pokedexes"`
+	VersionGroups  []NamedAPIResource[VersionGroup] `json:"version_groups"`
+}
+
+// Location is a place in a region. The pokemon found there are listed per
+// area, see SpecificLocationResponse.
+type Location struct {
+	ID          int                                          `json:"id"`
+	Name        string                                       `json:"name"`
+	Region      *NamedAPIResource[Region]                    `json:"region"`
+	Names       []Name                                       `json:"names"`
+	GameIndices []GenerationGameIndex                        `json:"game_indices"`
+	Areas       []NamedAPIResource[SpecificLocationResponse] `json:"areas"`
+}
+
+type Version struct {
+	ID           int                            `json:"id"`
+	Name         string                         `json:"name"`
+	Names        []Name                         `json:"names"`
+	VersionGroup NamedAPIResource[VersionGroup] `json:"version_group"`
+}
+
+type VersionGroup struct {
+	ID               int                          `json:"id"`
+	Name             string                       `json:"name"`
+	Order            int                          `json:"order"`
+	Generation       NamedAPIResource[Generation] `json:"generation"`
+	MoveLearnMethods []NamedAPIResource[any]      `json:"move_learn_methods"`
+	Pokedexes        []NamedAPIResource[any]      `json:"pokedexes"`
+	Regions          []NamedAPIResource[Region]   `json:"regions"`
+	Versions         []NamedAPIResource[Version]  `json:"versions"`
+}
+
+// Unmarshal decodes body into a T, reporting failures as a *DecodeError.
+func Unmarshal[T any](body []byte) (T, error) {
+	var v T
+	if err := json.Unmarshal(body, &v); err != nil {
+		return v, &DecodeError{Model: modelName[T](), Err: err}
+	}
+	return v, nil
+}
+
+// modelName names T in errors. References without a model decode into any.
+func modelName[T any]() string {
+	if name := reflect.TypeFor[T]().Name(); name != "" {
+		return name
+	}
+	return "resource"
+}
+
+// getResource downloads the resource at endpoint/nameOrID and decodes it.
+func getResource[T any](ctx context.Context, c *Client, endpoint, nameOrID string) (T, error) {
+	body, err := c.Get(ctx, c.ResourceURL(endpoint, nameOrID))
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Decoder[T](c)(body)
+}
+
+func (c *Client) Pokemon(ctx context.Context, nameOrID string) (Pokemon, error) {
+	return getResource[Pokemon](ctx, c, "pokemon", nameOrID)
+}
+
+func (c *Client) LocationArea(ctx context.Context, nameOrID string) (SpecificLocationResponse, error) {
+	return getResource[SpecificLocationResponse](ctx, c, "location-area", nameOrID)
+}
+
+func (c *Client) PokemonSpecies(ctx context.Context, nameOrID string) (PokemonSpecies, error) {
+	return getResource[PokemonSpecies](ctx, c, "pokemon-species", nameOrID)
+}
+
+// EvolutionChain takes an ID, evolution chains have no name.
+func (c *Client) EvolutionChain(ctx context.Context, id string) (EvolutionChain, error) {
+	return getResource[EvolutionChain](ctx, c, "evolution-chain", id)
+}
+
+func (c *Client) Move(ctx context.Context, nameOrID string) (Move, error) {
+	return getResource[Move](ctx, c, "move", nameOrID)
+}
+
+func (c *Client) Ability(ctx context.Context, nameOrID string) (Ability, error) {
+	return getResource[Ability](ctx, c, "ability", nameOrID)
+}
+
+func (c *Client) Type(ctx context.Context, nameOrID string) (Type, error) {
+	return getResource[Type](ctx, c, "type", nameOrID)
+}
+
+func (c *Client) Item(ctx context.Context, nameOrID string) (Item, error) {
+	return getResource[Item](ctx, c, "item", nameOrID)
+}
+
+func (c *Client) Berry(ctx context.Context, nameOrID string) (Berry, error) {
+	return getResource[Berry](ctx, c, "berry", nameOrID)
+}
+
+func (c *Client) Nature(ctx context.Context, nameOrID string) (Nature, error) {
+	return getResource[Nature](ctx, c, "nature", nameOrID)
+}
+
+func (c *Client) Generation(ctx context.Context, nameOrID string) (Generation, error) {
+	return getResource[Generation](ctx, c, "generation", nameOrID)
+}
+
+func (c *Client) Region(ctx context.Context, nameOrID string) (Region, error) {
+	return getResource[Region](ctx, c, "region", nameOrID)
+}
+
+func (c *Client) Location(ctx context.Context, nameOrID string) (Location, error) {
+	return getResource[Location](ctx, c, "location", nameOrID)
+}
+
+func (c *Client) Version(ctx context.Context, nameOrID string) (Version, error) {
+	return getResource[Version](ctx, c, "version", nameOrID)
+}
+
+func (c *Client) VersionGroup(ctx context.Context, nameOrID string) (VersionGroup, error) {
+	return getResource[VersionGroup](ctx, c, "version-group", nameOrID)
+}
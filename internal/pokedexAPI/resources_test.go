@@ -0,0 +1,238 @@
+package pokedexapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fixtureServer serves testdata/<endpoint>.json for every resource below
+// /<endpoint>/, whatever its name or ID, and testdata/<endpoint>-list.json
+// for every page of /<endpoint> itself.
+func fixtureServer(t *testing.T) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint := path.Dir(r.URL.Path)[1:]
+		if endpoint == "" {
+			endpoint = path.Base(r.URL.Path) + "-list"
+		}
+		body, err := os.ReadFile(filepath.Join("testdata", endpoint+".json"))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+}
+
+func TestResources(t *testing.T) {
+	client := fixtureServer(t)
+	ctx := context.Background()
+
+	cases := []struct {
+		name  string
+		check func(t *testing.T) error
+	}{
+		{"pokemon", func(t *testing.T) error {
+			pokemon, err := client.Pokemon(ctx, "sprigatito")
+			if err == nil && (pokemon.ID != 906 || pokemon.Cries.Legacy != nil || pokemon.Sprites.FrontDefault == nil ||
+				pokemon.Sprites.BackDefault != nil || pokemon.Types[0].Type.Name != "grass" || pokemon.Abilities[1].Ability.Name != "protean") {
+				t.Errorf("unexpected pokemon %+v", pokemon)
+			}
+			return err
+		}},
+		{"location-area", func(t *testing.T) error {
+			area, err := client.LocationArea(ctx, "canalave-city-area")
+			if err == nil && (area.Location.Name != "canalave-city" || len(area.PokemonEncounters) != 2 ||
+				area.PokemonEncounters[0].VersionDetails[0].EncounterDetails[1].Method.Name != "super-rod") {
+				t.Errorf("unexpected location area %+v", area)
+			}
+			return err
+		}},
+		{"location-area-list", func(t *testing.T) error {
+			page, err := client.Pager("location-area", 0).Next(ctx)
+			if err == nil && (page.Count != 1089 || page.Next == nil || page.Previous != nil || page.Results[0].Name != "canalave-city-area") {
+				t.Errorf("unexpected page %+v", page)
+			}
+			return err
+		}},
+		{"pokemon-species", func(t *testing.T) error {
+			species, err := client.PokemonSpecies(ctx, "pikachu")
+			if err == nil && (species.ID != 25 || species.CaptureRate != 190 || species.EvolvesFromSpecies.Name != "pichu" ||
+				species.EvolutionChain.URL != "https://pokeapi.co/api/v2/evolution-chain/10/" || len(species.Varieties) != 2) {
+				t.Errorf("unexpected species %+v", species)
+			}
+			return err
+		}},
+		{"evolution-chain", func(t *testing.T) error {
+			chain, err := client.EvolutionChain(ctx, "10")
+			if err != nil {
+				return err
+			}
+			pikachu := chain.Chain.EvolvesTo[0]
+			raichu := pikachu.EvolvesTo[0]
+			if !chain.Chain.IsBaby || chain.BabyTriggerItem != nil || *pikachu.EvolutionDetails[0].MinHappiness != 220 ||
+				raichu.Species.Name != "raichu" || raichu.EvolutionDetails[0].Item.Name != "thunder-stone" || raichu.EvolutionDetails[0].MinLevel != nil {
+				t.Errorf("unexpected evolution chain %+v", chain)
+			}
+			return nil
+		}},
+		{"move", func(t *testing.T) error {
+			move, err := client.Move(ctx, "thunderbolt")
+			if err == nil && (*move.Power != 90 || *move.PP != 15 || move.Meta.MinHits != nil || move.Meta.AilmentChance != 10 ||
+				move.Type.Name != "electric" || *move.PastValues[0].Power != 95 || move.ContestCombos.Normal.UseBefore != nil) {
+				t.Errorf("unexpected move %+v", move)
+			}
+			return err
+		}},
+		{"ability", func(t *testing.T) error {
+			ability, err := client.Ability(ctx, "static")
+			if err == nil && (!ability.IsMainSeries || ability.Pokemon[0].Pokemon.Name != "pikachu" ||
+				ability.EffectChanges[0].EffectEntries[0].Effect != "Has no overworld effect.") {
+				t.Errorf("unexpected ability %+v", ability)
+			}
+			return err
+		}},
+		{"type", func(t *testing.T) error {
+			electric, err := client.Type(ctx, "electric")
+			if err == nil && (electric.DamageRelations.NoDamageTo[0].Name != "ground" || len(electric.DamageRelations.HalfDamageTo) != 3 ||
+				len(electric.DamageRelations.NoDamageFrom) != 0 || electric.MoveDamageClass.Name != "special") {
+				t.Errorf("unexpected type %+v", electric)
+			}
+			return err
+		}},
+		{"item", func(t *testing.T) error {
+			item, err := client.Item(ctx, "potion")
+			if err == nil && (item.Cost != 200 || *item.FlingPower != 30 || item.FlingEffect != nil || item.Sprites.Default == nil ||
+				item.FlavorTextEntries[0].Text == "" || item.Category.Name != "healing") {
+				t.Errorf("unexpected item %+v", item)
+			}
+			return err
+		}},
+		{"berry", func(t *testing.T) error {
+			berry, err := client.Berry(ctx, "cheri")
+			if err == nil && (berry.GrowthTime != 3 || berry.Flavors[0].Flavor.Name != "spicy" || berry.Item.Name != "cheri-berry") {
+				t.Errorf("unexpected berry %+v", berry)
+			}
+			return err
+		}},
+		{"nature", func(t *testing.T) error {
+			nature, err := client.Nature(ctx, "bold")
+			if err == nil && (nature.IncreasedStat.Name != "defense" || nature.DecreasedStat.Name != "attack" ||
+				nature.MoveBattleStylePreferences[0].LowHPPreference != 32) {
+				t.Errorf("unexpected nature %+v", nature)
+			}
+			return err
+		}},
+		{"generation", func(t *testing.T) error {
+			generation, err := client.Generation(ctx, "generation-i")
+			if err == nil && (generation.MainRegion.Name != "kanto" || len(generation.PokemonSpecies) != 2 || len(generation.Abilities) != 0) {
+				t.Errorf("unexpected generation %+v", generation)
+			}
+			return err
+		}},
+		{"region", func(t *testing.T) error {
+			region, err := client.Region(ctx, "kanto")
+			if err == nil && (region.MainGeneration.Name != "generation-i" || region.Locations[0].Name != "pallet-town") {
+				t.Errorf("unexpected region %+v", region)
+			}
+			return err
+		}},
+		{"location", func(t *testing.T) error {
+			location, err := client.Location(ctx, "pallet-town")
+			if err == nil && (location.Region.Name != "kanto" || location.Areas[0].Name != "pallet-town-area") {
+				t.Errorf("unexpected location %+v", location)
+			}
+			return err
+		}},
+		{"version", func(t *testing.T) error {
+			version, err := client.Version(ctx, "red")
+			if err == nil && (version.ID != 1 || version.VersionGroup.Name != "red-blue" || version.Names[0].Name != "Red") {
+				t.Errorf("unexpected version %+v", version)
+			}
+			return err
+		}},
+		{"version-group", func(t *testing.T) error {
+			group, err := client.VersionGroup(ctx, "red-blue")
+			if err == nil && (group.Order != 1 || len(group.Versions) != 2 || group.Regions[0].Name != "kanto") {
+				t.Errorf("unexpected version group %+v", group)
+			}
+			return err
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.check(t); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// fixtureCache answers every URL with the fixture of its endpoint and
+// remembers which URLs were asked for.
+type fixtureCache struct {
+	urls []string
+}
+
+func (c *fixtureCache) Get(ctx context.Context, url string) ([]byte, error) {
+	c.urls = append(c.urls, url)
+	endpoint := path.Base(path.Dir(strings.TrimSuffix(url, "/")))
+	return os.ReadFile(filepath.Join("testdata", endpoint+".json"))
+}
+
+func TestResolve(t *testing.T) {
+	cache := &fixtureCache{}
+	client := NewClient(WithBaseURL("http://127.0.0.1:1"), WithCache(cache))
+	ctx := context.Background()
+
+	species, err := client.PokemonSpecies(ctx, "pikachu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := species.EvolutionChain.Resolve(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chain.ID != 10 {
+		t.Errorf("expected evolution chain 10, got %d", chain.ID)
+	}
+	stone, err := chain.Chain.EvolvesTo[0].EvolvesTo[0].EvolutionDetails[0].Item.Resolve(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// There is a single item fixture, a potion.
+	if stone.Name != "potion" {
+		t.Errorf("expected the item fixture, got %q", stone.Name)
+	}
+	generation, err := species.Generation.Resolve(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if generation.MainRegion.Name != "kanto" {
+		t.Errorf("expected generation-i, got %+v", generation)
+	}
+
+	expected := []string{
+		"http://127.0.0.1:1/pokemon-species/pikachu",
+		"https://pokeapi.co/api/v2/evolution-chain/10/",
+		"https://pokeapi.co/api/v2/item/83/",
+		"https://pokeapi.co/api/v2/generation/1/",
+	}
+	if !slices.Equal(cache.urls, expected) {
+		t.Errorf("expected every request to go through the cache, got %v", cache.urls)
+	}
+
+	if _, err := (NamedAPIResource[Move]{}).Resolve(ctx, client); err == nil {
+		t.Error("expected an empty reference to fail")
+	}
+}
@@ -0,0 +1,123 @@
+package pokedexapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy decides how often and how long a client waits before it sends a
+// failed GET again. Network errors, 429 and 5xx answers other than 501 are
+// retried; everything else fails at once.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt, so 1 or less disables retries.
+	MaxAttempts int
+	// BaseDelay is doubled after every attempt, up to MaxDelay. A random
+	// jitter of up to half the delay is subtracted so that clients that
+	// failed together do not retry together.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy retries twice, waiting about half a second and then a
+// second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// WithRetry replaces DefaultRetryPolicy. Use RetryPolicy{} to disable
+// retries.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// backoff returns the delay before attempt, where the first retry is attempt
+// 2.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for range attempt - 2 {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+	if p.MaxDelay > 0 {
+		delay = min(delay, p.MaxDelay)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay - rand.N(delay/2+1)
+}
+
+// retryDelay reports whether err is worth another attempt and how long to wait
+// before it. A Retry-After header takes precedence over the backoff, unless it
+// asks for a longer wait than MaxDelay, in which case the error is returned.
+func (p RetryPolicy) retryDelay(ctx context.Context, err error, attempt int) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+	var wait time.Duration
+	var rateLimited *RateLimitedError
+	var serverError *ServerError
+	switch {
+	case errors.As(err, &rateLimited):
+		wait = rateLimited.RetryAfter
+	case errors.As(err, &serverError):
+		if serverError.StatusCode == http.StatusNotImplemented {
+			return 0, false
+		}
+		wait = serverError.RetryAfter
+	case !transient(err):
+		return 0, false
+	}
+	if wait == 0 {
+		return p.backoff(attempt + 1), true
+	}
+	if p.MaxDelay > 0 && wait > p.MaxDelay {
+		return 0, false
+	}
+	return wait, true
+}
+
+// transient reports whether err is a network failure that may not happen
+// again, such as a dropped connection or an attempt that timed out. Errors
+// building the request, like an unsupported URL scheme, are permanent.
+func transient(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	// Every error of http.Client.Do is a *url.Error, which is a net.Error
+	// itself, so the error it wraps decides.
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		err = urlError.Err
+	}
+	var dnsError *net.DNSError
+	if errors.As(err, &dnsError) {
+		return !dnsError.IsNotFound
+	}
+	var netError net.Error
+	return errors.As(err, &netError)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
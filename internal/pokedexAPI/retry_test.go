@@ -0,0 +1,143 @@
+package pokedexapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer answers with the given status codes in turn and with 200 once
+// they run out. A code of 0 drops the connection without an answer. It counts
+// the requests it received.
+func flakyServer(t *testing.T, retryAfter string, codes ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if n > len(codes) {
+			w.Write([]byte(`{"id": 1, "name": "bulbasaur"}`))
+			return
+		}
+		if codes[n-1] == 0 {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+			return
+		}
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(codes[n-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// countingTransport counts the requests the client tried to send, including
+// those that never reached a server.
+type countingTransport struct {
+	attempts atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	t.attempts.Add(1)
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestRetry(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+	cases := []struct {
+		name       string
+		retryAfter string
+		scheme     string
+		codes      []int
+		attempts   int32
+		succeeds   bool
+	}{
+		{name: "dropped connection", codes: []int{0}, attempts: 2, succeeds: true},
+		{name: "unsupported scheme", scheme: "gopher", attempts: 1},
+		{name: "recovers", codes: []int{503, 502}, attempts: 3, succeeds: true},
+		{name: "gives up", codes: []int{500, 500, 500}, attempts: 3},
+		{name: "rate limited", codes: []int{429}, attempts: 2, succeeds: true},
+		{name: "not found", codes: []int{404}, attempts: 1},
+		{name: "not implemented", codes: []int{501}, attempts: 1},
+		{name: "retry after too long", retryAfter: "60", codes: []int{429}, attempts: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server, _ := flakyServer(t, c.retryAfter, c.codes...)
+			baseURL := server.URL
+			if c.scheme != "" {
+				baseURL = c.scheme + strings.TrimPrefix(baseURL, "http")
+			}
+			transport := &countingTransport{}
+			client := NewClient(WithBaseURL(baseURL), WithRetry(policy), WithHTTPClient(&http.Client{Transport: transport}))
+			_, err := client.Get(context.Background(), client.ResourceURL("pokemon", "1"))
+			if c.succeeds && err != nil {
+				t.Errorf("expected success, got %v", err)
+			}
+			if !c.succeeds && err == nil {
+				t.Error("expected an error")
+			}
+			if n := transport.attempts.Load(); n != c.attempts {
+				t.Errorf("expected %d attempts, got %d", c.attempts, n)
+			}
+		})
+	}
+}
+
+func TestRetryHonoursRetryAfter(t *testing.T) {
+	server, requests := flakyServer(t, "1", http.StatusServiceUnavailable)
+	client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond}))
+
+	start := time.Now()
+	if _, err := client.Get(context.Background(), client.ResourceURL("pokemon", "1")); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait for Retry-After, retried after %s", elapsed)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	server, requests := flakyServer(t, "", 503, 503, 503)
+	client := NewClient(WithBaseURL(server.URL), WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.Get(ctx, client.ResourceURL("pokemon", "1"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the context deadline, got %v", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt, ceiling := range map[int]time.Duration{
+		2: 100 * time.Millisecond,
+		3: 200 * time.Millisecond,
+		4: 400 * time.Millisecond,
+		8: time.Second,
+	} {
+		for range 100 {
+			delay := policy.backoff(attempt)
+			if delay < ceiling/2 || delay > ceiling {
+				t.Fatalf("backoff(%d) = %s, expected between %s and %s", attempt, delay, ceiling/2, ceiling)
+			}
+		}
+	}
+}
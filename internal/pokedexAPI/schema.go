@@ -0,0 +1,355 @@
+package pokedexapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ProblemKind says how a payload differs from the model it is decoded into.
+type ProblemKind int
+
+const (
+	// UnknownField is a field of the payload the model does not have.
+	UnknownField ProblemKind = iota
+	// MissingField is a field of the model the payload does not have.
+	MissingField
+	// TypeChanged is a field whose JSON type is not the one the model
+	// expects.
+	TypeChanged
+)
+
+func (k ProblemKind) String() string {
+	switch k {
+	case UnknownField:
+		return "unknown field"
+	case MissingField:
+		return "missing field"
+	case TypeChanged:
+		return "type changed"
+	}
+	return fmt.Sprintf("ProblemKind(%d)", int(k))
+}
+
+// SchemaProblem is one difference between a payload and its model. Paths
+// use [] for the elements of an array, so a field missing from every element
+// of a list is reported once.
+type SchemaProblem struct {
+	Kind ProblemKind
+	Path string
+	// Expected and Got are the JSON types of a field whose type changed.
+	Expected string
+	Got      string
+}
+
+func (p SchemaProblem) String() string {
+	if p.Kind == TypeChanged {
+		return fmt.Sprintf("%s: %s from %s to %s", p.Path, p.Kind, p.Expected, p.Got)
+	}
+	return fmt.Sprintf("%s: %s", p.Path, p.Kind)
+}
+
+// SchemaError is returned, wrapped in a DecodeError, when strict decoding
+// finds a payload that does not match its model.
+type SchemaError struct {
+	Problems []SchemaProblem
+}
+
+func (e *SchemaError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].String()
+	}
+	return fmt.Sprintf("%d schema problems, the first is %s", len(e.Problems), e.Problems[0])
+}
+
+// WithStrictDecoding makes the client decode resources with UnmarshalStrict,
+// so that changes to PokeAPI fail loudly instead of leaving zero values.
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// Decoder returns the function client decodes a T with: Unmarshal, or
+// UnmarshalStrict if the client was built WithStrictDecoding.
+func Decoder[T any](client *Client) func(body []byte) (T, error) {
+	if client.strict {
+		return UnmarshalStrict[T]
+	}
+	return Unmarshal[T]
+}
+
+// UnmarshalStrict is Unmarshal that rejects payloads that do not match T
+// exactly: unknown fields, missing fields and fields of another type are all
+// errors. Nullable fields, which are pointers, slices and maps, must be
+// present but may be null.
+func UnmarshalStrict[T any](body []byte) (T, error) {
+	var v T
+	problems, err := CheckSchema[T](body)
+	if err != nil {
+		return v, err
+	}
+	if len(problems) > 0 {
+		return v, &DecodeError{Model: modelName[T](), Err: &SchemaError{Problems: problems}}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&v); err != nil {
+		return v, &DecodeError{Model: modelName[T](), Err: err}
+	}
+	return v, nil
+}
+
+// CheckSchema compares body with the model T and returns every difference,
+// or a DecodeError if body is not JSON at all.
+func CheckSchema[T any](body []byte) ([]SchemaProblem, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		return nil, &DecodeError{Model: modelName[T](), Err: err}
+	}
+	var checker schemaChecker
+	checker.check(reflect.TypeFor[T](), v, "")
+	return checker.problems, nil
+}
+
+type schemaChecker struct {
+	problems []SchemaProblem
+}
+
+func (c *schemaChecker) report(problem SchemaProblem) {
+	if !slices.Contains(c.problems, problem) {
+		c.problems = append(c.problems, problem)
+	}
+}
+
+func (c *schemaChecker) check(t reflect.Type, v any, path string) {
+	switch t.Kind() {
+	case reflect.Interface:
+		return
+	case reflect.Pointer:
+		if v != nil {
+			c.check(t.Elem(), v, path)
+		}
+		return
+	case reflect.Struct:
+		object, ok := v.(map[string]any)
+		if !ok {
+			break
+		}
+		fields := jsonFields(t)
+		for _, field := range fields {
+			value, ok := lookupField(object, field.name)
+			if !ok {
+				c.report(SchemaProblem{Kind: MissingField, Path: joinPath(path, field.name)})
+				continue
+			}
+			c.check(field.typ, value, joinPath(path, field.name))
+		}
+		keys := make([]string, 0, len(object))
+		for key := range object {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if !slices.ContainsFunc(fields, func(f jsonField) bool { return strings.EqualFold(f.name, key) }) {
+				c.report(SchemaProblem{Kind: UnknownField, Path: joinPath(path, key)})
+			}
+		}
+		return
+	case reflect.Slice, reflect.Array:
+		// PokeAPI sends null for some empty lists, which decode to nil.
+		if v == nil && t.Kind() == reflect.Slice {
+			return
+		}
+		list, ok := v.([]any)
+		if !ok {
+			break
+		}
+		for _, element := range list {
+			c.check(t.Elem(), element, path+"[]")
+		}
+		return
+	case reflect.Map:
+		if v == nil {
+			return
+		}
+		object, ok := v.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, value := range object {
+			c.check(t.Elem(), value, path+"[]")
+		}
+		return
+	}
+	expected, got := jsonTypeOf(t), jsonType(v)
+	if expected != got && !(expected == "number" && got == "integer") {
+		if path == "" {
+			path = "."
+		}
+		c.report(SchemaProblem{Kind: TypeChanged, Path: path, Expected: expected, Got: got})
+	}
+}
+
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+// jsonFields lists the fields encoding/json decodes into t, including those
+// of embedded structs.
+func jsonFields(t reflect.Type) []jsonField {
+	var fields []jsonField
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(field.Type)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, jsonField{name: name, typ: field.Type})
+	}
+	return fields
+}
+
+// lookupField finds name in object the way encoding/json does, preferring an
+// exact match over a case-insensitive one.
+func lookupField(object map[string]any, name string) (any, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// jsonTypeOf is the JSON type encoding/json decodes into t.
+func jsonTypeOf(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return t.Kind().String()
+}
+
+// jsonType is the JSON type of v, as decoded with UseNumber.
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// SchemaReport is the outcome of checking the sample payload of one model.
+type SchemaReport struct {
+	Model    string
+	URL      string
+	Problems []SchemaProblem
+	// Err is set when the sample could not be downloaded or is not JSON.
+	Err error
+}
+
+type schemaSample struct {
+	model string
+	url   string
+	check func(body []byte) ([]SchemaProblem, error)
+}
+
+func sampleOf[T any](c *Client, resource string, path ...string) schemaSample {
+	return schemaSample{model: modelName[T](), url: c.ResourceURL(resource, path...), check: CheckSchema[T]}
+}
+
+// schemaSamples names a well-known resource for every model.
+func (c *Client) schemaSamples() []schemaSample {
+	return []schemaSample{
+		sampleOf[NamedAPIResourceList](c, "location-area"),
+		sampleOf[SpecificLocationResponse](c, "location-area", "canalave-city-area"),
+		sampleOf[Pokemon](c, "pokemon", "pikachu"),
+		sampleOf[PokemonSpecies](c, "pokemon-species", "pikachu"),
+		sampleOf[EvolutionChain](c, "evolution-chain", "10"),
+		sampleOf[Move](c, "move", "thunderbolt"),
+		sampleOf[Ability](c, "ability", "static"),
+		sampleOf[Type](c, "type", "electric"),
+		sampleOf[Item](c, "item", "potion"),
+		sampleOf[Berry](c, "berry", "cheri"),
+		sampleOf[Nature](c, "nature", "bold"),
+		sampleOf[Generation](c, "generation", "generation-i"),
+		sampleOf[Region](c, "region", "kanto"),
+		sampleOf[Location](c, "location", "pallet-town"),
+		sampleOf[Version](c, "version", "red"),
+		sampleOf[VersionGroup](c, "version-group", "red-blue"),
+	}
+}
+
+// CheckSchemas downloads a sample payload for every model and compares it
+// with the model. The samples always come from PokeAPI, never from the
+// client's cache, whose copies may predate a change. Reports are in a fixed
+// order, one per model.
+func (c *Client) CheckSchemas(ctx context.Context, workers int) []SchemaReport {
+	samples := c.schemaSamples()
+	urls := make([]string, len(samples))
+	for i, sample := range samples {
+		urls[i] = sample.url
+	}
+	download := func(ctx context.Context, url string) ([]byte, error) {
+		response, err := c.GetConditional(ctx, url, "", "")
+		return response.Body, err
+	}
+	reports := make([]SchemaReport, len(samples))
+	for i, result := range Batch(ctx, urls, workers, download, nil) {
+		report := SchemaReport{Model: samples[i].model, URL: result.URL, Err: result.Err}
+		if report.Err == nil {
+			report.Problems, report.Err = samples[i].check(result.Value)
+		}
+		reports[i] = report
+	}
+	return reports
+}
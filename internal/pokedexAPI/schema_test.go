@@ -0,0 +1,122 @@
+package pokedexapi
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFixturesMatchModels(t *testing.T) {
+	checks := map[string]func(body []byte) ([]SchemaProblem, error){
+		"location-area-list": CheckSchema[NamedAPIResourceList],
+		"location-area":      CheckSchema[SpecificLocationResponse],
+		"pokemon":            CheckSchema[Pokemon],
+		"pokemon-species":    CheckSchema[PokemonSpecies],
+		"evolution-chain":    CheckSchema[EvolutionChain],
+		"move":               CheckSchema[Move],
+		"ability":            CheckSchema[Ability],
+		"type":               CheckSchema[Type],
+		"item":               CheckSchema[Item],
+		"berry":              CheckSchema[Berry],
+		"nature":             CheckSchema[Nature],
+		"generation":         CheckSchema[Generation],
+		"region":             CheckSchema[Region],
+		"location":           CheckSchema[Location],
+		"version":            CheckSchema[Version],
+		"version-group":      CheckSchema[VersionGroup],
+	}
+	for name, check := range checks {
+		body, err := os.ReadFile(filepath.Join("testdata", name+".json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		problems, err := check(body)
+		if err != nil || len(problems) != 0 {
+			t.Errorf("%s: expected the fixture to match its model, got %v %v", name, problems, err)
+		}
+	}
+}
+
+type schemaModel struct {
+	Name    string                     `json:"name"`
+	Order   int                        `json:"order"`
+	Parent  *NamedAPIResource[any]     `json:"parent"`
+	Entries []NamedAPIResource[Region] `json:"entries"`
+}
+
+func TestCheckSchema(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		expected []SchemaProblem
+	}{
+		{"match", `{"name": "a", "order": 1, "parent": null, "entries": []}`, nil},
+		{"unknown", `{"name": "a", "order": 1, "parent": null, "entries": [], "is_new": true}`, []SchemaProblem{
+			{Kind: UnknownField, Path: "is_new"},
+		}},
+		{"missing", `{"name": "a", "entries": [{"name": "kanto", "url": ""}, {"name": "johto"}, {"name": "hoenn"}]}`, []SchemaProblem{
+			{Kind: MissingField, Path: "order"},
+			{Kind: MissingField, Path: "parent"},
+			{Kind: MissingField, Path: "entries[].url"},
+		}},
+		{"type changed", `{"name": 1, "order": 1.5, "parent": {"name": "b", "url": 2}, "entries": {}}`, []SchemaProblem{
+			{Kind: TypeChanged, Path: "name", Expected: "string", Got: "integer"},
+			{Kind: TypeChanged, Path: "order", Expected: "integer", Got: "number"},
+			{Kind: TypeChanged, Path: "parent.url", Expected: "string", Got: "integer"},
+			{Kind: TypeChanged, Path: "entries", Expected: "array", Got: "object"},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			problems, err := CheckSchema[schemaModel]([]byte(c.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(problems, c.expected) {
+				t.Errorf("expected %v, got %v", c.expected, problems)
+			}
+		})
+	}
+
+	if _, err := CheckSchema[schemaModel]([]byte("<html>")); !errors.As(err, new(*DecodeError)) {
+		t.Errorf("expected a DecodeError for a body that is not JSON, got %v", err)
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	body := []byte(`{"name": "a", "order": 1, "parent": null, "entries": [], "is_new": true}`)
+	if _, err := Unmarshal[schemaModel](body); err != nil {
+		t.Fatalf("expected lenient decoding to ignore the unknown field, got %v", err)
+	}
+	_, err := UnmarshalStrict[schemaModel](body)
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) || !errors.As(err, new(*DecodeError)) || schemaErr.Problems[0].Path != "is_new" {
+		t.Errorf("expected a schema error about is_new, got %v", err)
+	}
+
+	client := NewClient(WithCache(&fixtureCache{}), WithStrictDecoding())
+	if _, err := client.Move(context.Background(), "thunderbolt"); err != nil {
+		t.Errorf("expected the move fixture to decode strictly, got %v", err)
+	}
+}
+
+func TestCheckSchemas(t *testing.T) {
+	cache := &fixtureCache{}
+	client := fixtureServer(t)
+	WithCache(cache)(client)
+	reports := client.CheckSchemas(context.Background(), 4)
+	if len(cache.urls) != 0 {
+		t.Errorf("expected the samples to bypass the cache, got %v", cache.urls)
+	}
+	if len(reports) != len(client.schemaSamples()) {
+		t.Fatalf("expected a report per model, got %d", len(reports))
+	}
+	for _, report := range reports {
+		if report.Err != nil || len(report.Problems) != 0 {
+			t.Errorf("%s: expected no problems, got %v %v", report.Model, report.Problems, report.Err)
+		}
+	}
+}
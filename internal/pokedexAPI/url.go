@@ -0,0 +1,58 @@
+package pokedexapi
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// CanonicalURL normalizes a PokeAPI URL so that equivalent spellings share a
+// cache key. Scheme, host and path are lower cased, trailing slashes and
+// fragments are dropped and query parameters are sorted.
+func CanonicalURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return raw
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Path = strings.ToLower(strings.TrimRight(u.Path, "/"))
+	u.RawPath = ""
+	u.Fragment = ""
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+	return u.String()
+}
+
+// ResourceAliases returns the canonical ID and name URLs of the resource
+// found at resourceURL, so it can be looked up by either later on. It returns
+// nothing for bodies that are not a single named resource, such as lists.
+func ResourceAliases(resourceURL string, body []byte) []string {
+	var resource struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &resource); err != nil || resource.ID == 0 || resource.Name == "" {
+		return nil
+	}
+	u, err := url.Parse(CanonicalURL(resourceURL))
+	if err != nil || u.RawQuery != "" {
+		return nil
+	}
+	id := strconv.Itoa(resource.ID)
+	name := strings.ToLower(resource.Name)
+	dir, last := path.Split(u.Path)
+	if last != id && last != name {
+		return nil
+	}
+
+	aliases := make([]string, 0, 2)
+	for _, segment := range []string{id, name} {
+		u.Path = dir + segment
+		aliases = append(aliases, u.String())
+	}
+	return aliases
+}
@@ -0,0 +1,52 @@
+package pokedexapi
+
+import "testing"
+
+func TestCanonicalURL(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{
+			input:    "https://pokeapi.co/api/v2/pokemon/Pikachu",
+			expected: "https://pokeapi.co/api/v2/pokemon/pikachu",
+		},
+		{
+			input:    "https://pokeapi.co/api/v2/pokemon/pikachu/",
+			expected: "https://pokeapi.co/api/v2/pokemon/pikachu",
+		},
+		{
+			input:    "HTTPS://PokeAPI.co/api/v2/location-area/",
+			expected: "https://pokeapi.co/api/v2/location-area",
+		},
+		{
+			input:    "https://pokeapi.co/api/v2/location-area?offset=20&limit=20",
+			expected: "https://pokeapi.co/api/v2/location-area?limit=20&offset=20",
+		},
+	}
+
+	for _, c := range cases {
+		if actual := CanonicalURL(c.input); actual != c.expected {
+			t.Errorf("CanonicalURL(%q) = %q, expected %q", c.input, actual, c.expected)
+		}
+	}
+}
+
+func TestResourceAliases(t *testing.T) {
+	body := []byte(`{"id": 25, "name": "pikachu"}`)
+	expected := []string{
+		"https://pokeapi.co/api/v2/pokemon/25",
+		"https://pokeapi.co/api/v2/pokemon/pikachu",
+	}
+	for _, resourceURL := range expected {
+		aliases := ResourceAliases(resourceURL, body)
+		if len(aliases) != 2 || aliases[0] != expected[0] || aliases[1] != expected[1] {
+			t.Errorf("ResourceAliases(%q) = %v, expected %v", resourceURL, aliases, expected)
+		}
+	}
+
+	list := []byte(`{"count": 1, "results": []}`)
+	if aliases := ResourceAliases("https://pokeapi.co/api/v2/pokemon", list); aliases != nil {
+		t.Errorf("expected no aliases for a list, got %v", aliases)
+	}
+}
@@ -2,10 +2,16 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	pokecache "github.com/kwekkwekpatu/gokedex/internal/pokecache"
@@ -13,11 +19,123 @@ import (
 )
 
 var cliName string = "gokedex"
-var nextURL string = "https://pokeapi.co/api/v2/location-area"
-var previousURL any
+
 var commandHistory []string
 var historyIndex int = -1
 
+// PokeAPI data rarely changes, so responses persisted to disk are reused
+// across sessions for much longer than they stay in memory.
+const diskCacheTTL = 7 * 24 * time.Hour
+
+// The in-memory cache is bounded by size, so entries can stay around much
+// longer than they used to without growing the session without limit.
+const (
+	memoryCacheInterval = 5 * time.Minute
+	memoryCacheEntries  = 4000
+	memoryCacheBytes    = 32 << 20
+	memoryCacheShards   = 16
+)
+
+// Expired responses are kept so they can be revalidated with a conditional
+// request, and for a while they are answered from right away while that
+// request runs in the background.
+const (
+	staleRetention       = 24 * time.Hour
+	staleWhileRevalidate = time.Hour
+)
+
+// A resource's name and ID URLs always refer to the same resource, so the
+// aliases between them are kept as long as persisted responses.
+const aliasTTL = diskCacheTTL
+
+// Lookups of names that do not exist, usually typos, are remembered briefly
+// so that retrying them fails right away.
+const notFoundTTL = 10 * time.Minute
+
+// Paginated listings change as PokeAPI grows, while individual resources are
+// effectively static, so they are kept in memory for different durations.
+const (
+	locationListTTL = time.Minute
+	locationTTL     = memoryCacheInterval
+	pokemonTTL      = 6 * time.Hour
+	resourceTTL     = 6 * time.Hour
+)
+
+// PokeAPI asks clients to limit their request volume. Bursts cover a few
+// commands in a row, bulk downloads such as prefetch settle at the rate.
+const (
+	apiRequestsPerSecond = 10
+	apiBurst             = 20
+)
+
+// locationPageSize is the number of locations map and mapb show at once.
+const locationPageSize = 20
+
+// exploreWorkers is the number of pokemon "explore <location> details" loads
+// in parallel.
+const exploreWorkers = 8
+
+// Decoded responses are kept next to the byte cache so that hot entries are
+// not unmarshalled again on every command.
+const typedCacheEntries = 200
+
+// config holds the API client and caches shared by all commands.
+type config struct {
+	client *pokedexapi.Client
+	cache  pokecache.Cache
+
+	// aliases lets a resource fetched by ID be served from the cache when it
+	// is asked for by name, and the other way round, also after a restart.
+	aliases *pokecache.Aliases
+
+	locations *pokecache.TypedCache[pokedexapi.NamedAPIResourceList]
+	location  *pokecache.TypedCache[pokedexapi.SpecificLocationResponse]
+	pokemon   *pokecache.TypedCache[pokedexapi.Pokemon]
+
+	// locationPager remembers where map and mapb are in the location-area
+	// listing.
+	locationPager *pokedexapi.Pager
+}
+
+// newConfig builds the client from opts, reading every resource it resolves
+// through cache.
+func newConfig(cache pokecache.Cache, opts ...pokedexapi.Option) *config {
+	limit := pokecache.WithMaxEntries(typedCacheEntries)
+	cfg := &config{
+		cache:   cache,
+		aliases: pokecache.NewPersistentAliases(cache, aliasTTL),
+	}
+	cfg.client = pokedexapi.NewClient(append(opts, pokedexapi.WithCache(cfg))...)
+	cfg.locations = pokecache.NewTypedCache(cache, pokedexapi.Decoder[pokedexapi.NamedAPIResourceList](cfg.client), limit)
+	cfg.location = pokecache.NewTypedCache(cache, pokedexapi.Decoder[pokedexapi.SpecificLocationResponse](cfg.client), limit)
+	cfg.pokemon = pokecache.NewTypedCache(cache, pokedexapi.Decoder[pokedexapi.Pokemon](cfg.client), limit)
+	cfg.locationPager = pokedexapi.NewPager(cfg.client.ResourceURL("location-area"), locationPageSize,
+		func(ctx context.Context, url string) (pokedexapi.NamedAPIResourceList, error) {
+			return fetchTyped(ctx, cfg, cfg.locations, url, locationListTTL)
+		})
+	return cfg
+}
+
+// Get implements pokedexapi.Cache, so that resources the client resolves are
+// read through the byte cache like everything else. A cached 404 is reported
+// as a pokedexapi.NotFoundError, as the client would have.
+func (cfg *config) Get(ctx context.Context, url string) ([]byte, error) {
+	body, err := fetch(ctx, cfg, url, resourceTTL)
+	if errors.Is(err, pokecache.ErrNotFound) {
+		return nil, &pokedexapi.NotFoundError{URL: url}
+	}
+	return body, err
+}
+
+// purge drops every cached response, raw and decoded, whose URL starts with
+// prefix and reports how many raw responses were removed.
+func (cfg *config) purge(prefix string) int {
+	cfg.locations.PurgePrefix(prefix)
+	cfg.location.PurgePrefix(prefix)
+	cfg.pokemon.PurgePrefix(prefix)
+	return pokecache.PurgePrefix(cfg.cache, prefix)
+}
+
 type Pokedex struct {
 	pokedex map[string]pokedexapi.Pokemon
 }
@@ -38,7 +156,7 @@ func (p *Pokedex) GetPokemon(name string) (pokedexapi.Pokemon, bool) {
 type cliCommand struct {
 	name        string
 	description string
-	callback    func(cache *pokecache.Cache, dex *Pokedex, args ...string) error
+	callback    func(cfg *config, dex *Pokedex, args ...string) error
 }
 
 func getCommands() map[string]cliCommand {
@@ -83,14 +201,75 @@ func getCommands() map[string]cliCommand {
 			description: "Shows all the pokemon in your pokedex",
 			callback:    showPokedex,
 		},
+		"prefetch": {
+			name:        "prefetch",
+			description: "Download every location and the pokemon found there",
+			callback:    commandPrefetch,
+		},
+		"cache": {
+			name:        "cache",
+			description: "Inspect or clean up the response cache",
+			callback:    commandCache,
+		},
+		"api": {
+			name:        "api",
+			description: "Check that PokeAPI still matches the models gokedex decodes",
+			callback:    commandAPI,
+		},
 	}
 }
 
 func main() {
+	backend := flag.String("cache", "memory", "cache backend to use: memory or dir")
+	baseURL := flag.String("api", pokedexapi.DefaultBaseURL, "base URL of the PokeAPI instance to use")
+	timeout := flag.Duration("timeout", pokedexapi.DefaultTimeout, "timeout for a single API request")
+	attempts := flag.Int("attempts", pokedexapi.DefaultRetryPolicy.MaxAttempts, "how often a failed API request is tried before giving up")
+	rate := flag.Float64("rate", apiRequestsPerSecond, "maximum API requests per second, 0 for no limit")
+	burst := flag.Int("burst", apiBurst, "number of API requests allowed at once before -rate applies")
+	debug := flag.Bool("debug", false, "report throttled and retried API requests")
+	strict := flag.Bool("strict", false, "fail on API responses with unknown, missing or retyped fields")
+	flag.Parse()
+
 	commands := getCommands()
 
 	dex := NewPokedex()
-	cache := pokecache.NewCache(5 * time.Second)
+	cache, err := newCache(*backend)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer cache.Close()
+	clientOpts := []pokedexapi.Option{
+		pokedexapi.WithBaseURL(*baseURL),
+		pokedexapi.WithTimeout(*timeout),
+		pokedexapi.WithRetry(retryPolicy(*attempts)),
+	}
+	if *rate > 0 {
+		clientOpts = append(clientOpts, pokedexapi.WithRateLimiter(pokedexapi.NewRateLimiter(*rate, *burst)))
+	}
+	if *debug {
+		clientOpts = append(clientOpts, pokedexapi.WithDebug(os.Stderr))
+	}
+	if *strict {
+		clientOpts = append(clientOpts, pokedexapi.WithStrictDecoding())
+	}
+	cfg := newConfig(cache, clientOpts...)
+
+	// Arguments run a single command instead of the REPL, as in
+	// "gokedex api check".
+	if flag.NArg() > 0 {
+		command, exists := commands[flag.Arg(0)]
+		if !exists {
+			fmt.Fprintln(os.Stderr, "Unknown command: ", flag.Arg(0))
+			os.Exit(2)
+		}
+		if err := command.callback(cfg, dex, flag.Args()[1:]...); err != nil {
+			fmt.Fprintln(os.Stderr, "Error executing command: ", describeError(err))
+			cache.Close()
+			os.Exit(1)
+		}
+		return
+	}
 	scanner := bufio.NewScanner(os.Stdin)
 
 	bootGokedex()
@@ -104,9 +283,9 @@ func main() {
 
 		command, exists := commands[commandName]
 		if exists {
-			err := command.callback(cache, dex, args...)
+			err := command.callback(cfg, dex, args...)
 			if err != nil {
-				fmt.Println("Error executing command: ", err)
+				fmt.Println("Error executing command: ", describeError(err))
 			}
 		} else {
 			fmt.Println("Unknown command: ", input)
@@ -118,6 +297,83 @@ func main() {
 	}
 }
 
+// newCache builds the cache backend selected on the command line. The memory
+// backend gets a disk tier below the user cache directory when there is one.
+func newCache(backend string) (pokecache.Cache, error) {
+	var reportOnce sync.Once
+	opts := []pokecache.Option{
+		pokecache.WithRevalidation(staleRetention),
+		pokecache.WithStaleWhileRevalidate(staleWhileRevalidate),
+		pokecache.WithNegativeTTL(notFoundTTL),
+		pokecache.WithCorruptionHandler(func(err *pokecache.CorruptError) {
+			fmt.Fprintln(os.Stderr, "Dropped a damaged cache entry:", err)
+		}),
+		// A broken cache directory fails every write, so only the first error
+		// is shown. The others are counted in cache stats.
+		pokecache.WithErrorHandler(func(err error) {
+			reportOnce.Do(func() {
+				fmt.Fprintln(os.Stderr, "The cache is not working, responses will not be kept:", err)
+			})
+		}),
+	}
+	userCacheDir, dirErr := os.UserCacheDir()
+	dir := filepath.Join(userCacheDir, cliName)
+
+	switch backend {
+	case "memory":
+		opts = append(opts,
+			pokecache.WithMaxEntries(memoryCacheEntries),
+			pokecache.WithMaxBytes(memoryCacheBytes),
+			pokecache.WithShards(memoryCacheShards),
+		)
+		if dirErr == nil {
+			opts = append(opts, pokecache.WithDiskTier(dir, diskCacheTTL))
+		}
+		return pokecache.NewMemoryCache(memoryCacheInterval, opts...), nil
+	case "dir":
+		if dirErr != nil {
+			return nil, dirErr
+		}
+		return pokecache.NewDirCache(filepath.Join(dir, "responses"), diskCacheTTL, opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", backend)
+	}
+}
+
+// retryPolicy is the default retry policy with a different number of
+// attempts.
+func retryPolicy(attempts int) pokedexapi.RetryPolicy {
+	policy := pokedexapi.DefaultRetryPolicy
+	policy.MaxAttempts = attempts
+	return policy
+}
+
+// describeError turns API failures into a message that says what the user
+// can do about them.
+func describeError(err error) string {
+	var rateLimited *pokedexapi.RateLimitedError
+	var serverError *pokedexapi.ServerError
+	var decodeError *pokedexapi.DecodeError
+	var schemaError *pokedexapi.SchemaError
+	switch {
+	case errors.As(err, &rateLimited):
+		if rateLimited.RetryAfter > 0 {
+			return fmt.Sprintf("PokeAPI is limiting requests, try again in %s.", rateLimited.RetryAfter)
+		}
+		return "PokeAPI is limiting requests, try again in a moment."
+	case errors.As(err, &serverError):
+		return fmt.Sprintf("PokeAPI is having trouble (%d), try again later.", serverError.StatusCode)
+	case errors.As(err, &schemaError) && errors.As(err, &decodeError):
+		return fmt.Sprintf("PokeAPI changed the shape of %s (%v), run api check for details.", decodeError.Model, schemaError)
+	case errors.As(err, &decodeError):
+		return fmt.Sprintf("PokeAPI sent a response that is not a %s, try cache purge if it keeps happening (%v).", decodeError.Model, decodeError.Err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "PokeAPI did not answer in time, try again or start gokedex with a longer -timeout."
+	default:
+		return err.Error()
+	}
+}
+
 func printPromt() error {
 	fmt.Print(cliName, "> ")
 	return nil
@@ -128,55 +384,66 @@ func bootGokedex() error {
 	return nil
 }
 
-func commandHelp(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
+func commandHelp(cfg *config, dex *Pokedex, args ...string) error {
 	fmt.Println("Welcome to the Gokedex!")
 	fmt.Println("Usage:")
 	fmt.Println("  help: Displays a help message")
 	fmt.Println("  exit: Exit the Gokedex")
-	fmt.Println("  map: Print the next 20 locations")
+	fmt.Println("  map [offset]: Print the next 20 locations, or the 20 locations starting at offset")
 	fmt.Println("  mapb: Print the previous 20 locations")
 	fmt.Println("  explore [location]: Shows the pokemon that can be found in the given location")
+	fmt.Println("  explore [location] details: Also shows the types and base experience of those pokemon")
 	fmt.Println("  catch [pokemon]: Attempts to catch the given pokemon")
 	fmt.Println("  inspect [pokemon]: Shows the information of the selected pokemon if the pokemon has been added to the pokedex")
 	fmt.Println("  pokedex: Show all the pokemon currently in your pokedex")
+	fmt.Println("  prefetch: Download every location and its pokemon so exploring and catching are instant")
+	fmt.Println("  cache stats: Show cache hits, misses and sizes")
+	fmt.Println("  cache keys: List the cached URLs")
+	fmt.Println("  cache purge [prefix]: Remove cached URLs starting with the given prefix")
+	fmt.Println("  cache clear: Remove everything from the cache")
+	fmt.Println("  cache verify: Check every cached entry and remove the damaged ones")
+	fmt.Println("  cache export [file]: Write the cache to a snapshot file for offline use")
+	fmt.Println("  cache import [file]: Load a snapshot file written by cache export")
+	fmt.Println("  api check: Compare a sample response of every model with what gokedex expects")
 	return nil
 }
 
-func commandExit(ccache *pokecache.Cache, dex *Pokedex, args ...string) error {
+func commandExit(ccfg *config, dex *Pokedex, args ...string) error {
 	fmt.Println("Closing the Gokedex!")
 	os.Exit(0)
 	return nil
 }
 
-func displayNext(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
-	body, err := fetch(nextURL, cache)
-	if err != nil {
+func displayNext(cfg *config, dex *Pokedex, args ...string) error {
+	var locations pokedexapi.NamedAPIResourceList
+	var err error
+	if len(args) > 0 && args[0] != "" {
+		offset, convErr := strconv.Atoi(args[0])
+		if convErr != nil || offset < 0 {
+			return fmt.Errorf("Invalid offset: %s", args[0])
+		}
+		locations, err = cfg.locationPager.Seek(context.Background(), offset)
+	} else {
+		locations, err = cfg.locationPager.Next(context.Background())
+	}
+	if errors.Is(err, pokedexapi.ErrNoNextPage) {
+		fmt.Println("You are already at the last locations")
 		return err
 	}
-	locations, err := pokedexapi.UnmarshalLocations(body)
 	if err != nil {
 		return err
 	}
 
-	display(locations)
 	display(locations)
 	return nil
 }
 
-func displayPrevious(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
-	if previousURL == nil {
+func displayPrevious(cfg *config, dex *Pokedex, args ...string) error {
+	locations, err := cfg.locationPager.Previous(context.Background())
+	if errors.Is(err, pokedexapi.ErrNoPreviousPage) {
 		fmt.Println("You are already at the first locations")
-		return fmt.Errorf("No previousURL")
-	}
-	url, ok := previousURL.(string)
-	if !ok {
-		return fmt.Errorf("previousURL not a string")
-	}
-	body, err := fetch(url, cache)
-	if err != nil {
 		return err
 	}
-	locations, err := pokedexapi.UnmarshalLocations(body)
 	if err != nil {
 		return err
 	}
@@ -185,9 +452,7 @@ func displayPrevious(cache *pokecache.Cache, dex *Pokedex, args ...string) error
 	return nil
 }
 
-func display(locations pokedexapi.LocationsResponse) error {
-	nextURL = locations.Next
-	previousURL = locations.Previous
+func display(locations pokedexapi.NamedAPIResourceList) error {
 	for _, location := range locations.Results {
 		locationName := location.Name
 		fmt.Println(locationName)
@@ -195,23 +460,26 @@ func display(locations pokedexapi.LocationsResponse) error {
 	return nil
 }
 
-func exploreLocation(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
-	baseURL := "https://pokeapi.co/api/v2/location-area/"
+func exploreLocation(cfg *config, dex *Pokedex, args ...string) error {
 	location := args[0]
+	details := len(args) > 1 && args[1] == "details"
 	fmt.Println("Exploring " + location + "...")
 	if location == "" {
 		return fmt.Errorf("Invalid location name")
 	}
-	url := baseURL + location
-	body, err := fetch(url, cache)
-	if err != nil {
-		return err
+	url := cfg.client.ResourceURL("location-area", location)
+	locationData, err := fetchTyped(context.Background(), cfg, cfg.location, url, locationTTL)
+	if errors.Is(err, pokecache.ErrNotFound) {
+		return fmt.Errorf("no such location: %s", location)
 	}
-	locationData, err := pokedexapi.UnmarshalLocation(body)
 	if err != nil {
 		return err
 	}
 	fmt.Println("Found Pokemon:")
+	if details {
+		printEncounterDetails(cfg, locationData)
+		return nil
+	}
 	for _, encouter := range locationData.PokemonEncounters {
 		pokemon := encouter.Pokemon
 		println("- " + pokemon.Name)
@@ -219,27 +487,72 @@ func exploreLocation(cache *pokecache.Cache, dex *Pokedex, args ...string) error
 	return nil
 }
 
-func fetch(url string, cache *pokecache.Cache) ([]byte, error) {
-	body, exists := cache.Get(url)
-	defer cache.Add(url, body)
-	if exists {
-		return body, nil
+// printEncounterDetails lists the pokemon of a location with their types and
+// base experience, loading them in parallel. A pokemon that fails to load is
+// listed with its error instead.
+func printEncounterDetails(cfg *config, location pokedexapi.SpecificLocationResponse) {
+	refs := make([]pokedexapi.NamedAPIResource[pokedexapi.Pokemon], len(location.PokemonEncounters))
+	for i, encounter := range location.PokemonEncounters {
+		refs[i] = encounter.Pokemon
+	}
+	results := pokedexapi.ResolveMany(context.Background(), cfg.client, refs, exploreWorkers)
+	for i, result := range results {
+		if result.Err != nil {
+			fmt.Printf("- %s: %s\n", refs[i].Name, describeError(result.Err))
+			continue
+		}
+		pokemon := result.Value
+		types := make([]string, len(pokemon.Types))
+		for j, pokeType := range pokemon.Types {
+			types[j] = pokeType.Type.Name
+		}
+		fmt.Printf("- %s (%s), base experience %d\n", pokemon.Name, strings.Join(types, "/"), pokemon.BaseExperience)
 	}
-	return pokedexapi.Get(url)
 }
 
-func catch(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
-	baseUrl := "https://pokeapi.co/api/v2/pokemon/"
+// fetchTyped returns the decoded response for url, going through the decoded
+// cache, the byte cache and finally PokeAPI.
+func fetchTyped[T any](ctx context.Context, cfg *config, typed *pokecache.TypedCache[T], url string, ttl time.Duration) (T, error) {
+	key := cfg.aliases.Resolve(pokedexapi.CanonicalURL(url))
+	return typed.GetOrFetch(ctx, key, ttl, cfg.fetchFunc(key))
+}
+
+// fetchFunc downloads key from PokeAPI, revalidating a stale cached copy, and
+// records the name and ID aliases of the resource it returns. Requests are
+// bounded by the client's timeout and by the caller's context.
+func (cfg *config) fetchFunc(key string) pokecache.FetchFunc {
+	return func(ctx context.Context, stale pokecache.Validators) (pokecache.Response, error) {
+		response, err := cfg.client.GetConditional(ctx, key, stale.ETag, stale.LastModified)
+		if errors.Is(err, pokedexapi.ErrNotFound) {
+			return pokecache.Response{NotFound: true}, nil
+		}
+		if err != nil {
+			return pokecache.Response{}, err
+		}
+		for _, alias := range pokedexapi.ResourceAliases(key, response.Body) {
+			cfg.aliases.Add(alias, key)
+		}
+		return pokecache.Response{
+			Val: response.Body,
+			Validators: pokecache.Validators{
+				ETag:         response.ETag,
+				LastModified: response.LastModified,
+			},
+			NotModified: response.NotModified,
+		}, nil
+	}
+}
+
+func catch(cfg *config, dex *Pokedex, args ...string) error {
 	nameOrId := args[0]
 	if nameOrId == "" {
 		return fmt.Errorf("No pokemon name or id given.")
 	}
-	url := baseUrl + nameOrId
-	body, err := fetch(url, cache)
-	if err != nil {
-		return err
+	url := cfg.client.ResourceURL("pokemon", nameOrId)
+	pokemonData, err := fetchTyped(context.Background(), cfg, cfg.pokemon, url, pokemonTTL)
+	if errors.Is(err, pokecache.ErrNotFound) {
+		return fmt.Errorf("no such pokemon: %s", nameOrId)
 	}
-	pokemonData, err := pokedexapi.UnmarshalPokemon(body)
 	if err != nil {
 		return err
 	}
@@ -267,7 +580,7 @@ func tryCatchPokemon(pokemon pokedexapi.Pokemon) bool {
 	return false
 }
 
-func inspect(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
+func inspect(cfg *config, dex *Pokedex, args ...string) error {
 	if len(args) == 0 || args[0] == "" {
 		fmt.Println("No pokemon selected for inspection")
 		return nil
@@ -296,7 +609,7 @@ func printPokemon(pokemon pokedexapi.Pokemon) {
 	}
 }
 
-func showPokedex(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
+func showPokedex(cfg *config, dex *Pokedex, args ...string) error {
 	if len(dex.pokedex) == 0 {
 		fmt.Println("Your pokedex is empty.")
 		fmt.Println("Try catching some pokemon first!")
@@ -309,6 +622,150 @@ func showPokedex(cache *pokecache.Cache, dex *Pokedex, args ...string) error {
 	return nil
 }
 
+func commandCache(cfg *config, dex *Pokedex, args ...string) error {
+	if len(args) == 0 || args[0] == "" {
+		return fmt.Errorf("Missing cache subcommand, try: stats, keys, purge, clear, verify, export or import")
+	}
+	switch args[0] {
+	case "stats":
+		printCacheStats(cfg.cache.Stats())
+	case "keys":
+		keys := cfg.cache.Keys()
+		if len(keys) == 0 {
+			fmt.Println("The cache is empty.")
+			return nil
+		}
+		for _, key := range keys {
+			fmt.Println(" - " + key)
+		}
+	case "purge":
+		if len(args) < 2 || args[1] == "" {
+			return fmt.Errorf("No URL prefix given.")
+		}
+		purged := cfg.purge(args[1])
+		fmt.Printf("Purged %d cached URLs.\n", purged)
+	case "clear":
+		cfg.purge("")
+		fmt.Println("Cache cleared.")
+	case "verify":
+		return verifyCache(cfg.cache)
+	case "export":
+		if len(args) < 2 || args[1] == "" {
+			return fmt.Errorf("No snapshot file given.")
+		}
+		return exportCache(cfg.cache, args[1])
+	case "import":
+		if len(args) < 2 || args[1] == "" {
+			return fmt.Errorf("No snapshot file given.")
+		}
+		return importCache(cfg.cache, args[1])
+	default:
+		return fmt.Errorf("Unknown cache subcommand: %s", args[0])
+	}
+	return nil
+}
+
+// schemaCheckWorkers is the number of samples "api check" downloads at once.
+const schemaCheckWorkers = 4
+
+func commandAPI(cfg *config, dex *Pokedex, args ...string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("Missing api subcommand, try: check")
+	}
+	return checkAPI(cfg)
+}
+
+// checkAPI reports, per model, how PokeAPI's sample responses differ from
+// the structs gokedex decodes them into. It fails if any of them do, so that
+// "gokedex api check" can run in scripts.
+func checkAPI(cfg *config) error {
+	drifted, failed := 0, 0
+	for _, report := range cfg.client.CheckSchemas(context.Background(), schemaCheckWorkers) {
+		switch {
+		case report.Err != nil:
+			failed++
+			fmt.Printf("%s: %s\n", report.Model, describeError(report.Err))
+		case len(report.Problems) == 0:
+			fmt.Printf("%s: ok\n", report.Model)
+		default:
+			drifted++
+			fmt.Printf("%s: %d problems in %s\n", report.Model, len(report.Problems), report.URL)
+			for _, problem := range report.Problems {
+				fmt.Println(" - " + problem.String())
+			}
+		}
+	}
+	if drifted > 0 {
+		return fmt.Errorf("%d models do not match PokeAPI", drifted)
+	}
+	if failed > 0 {
+		return fmt.Errorf("could not check %d models", failed)
+	}
+	fmt.Println("Every model matches PokeAPI.")
+	return nil
+}
+
+func verifyCache(cache pokecache.Cache) error {
+	corrupt, err := pokecache.Verify(cache)
+	for _, entry := range corrupt {
+		fmt.Println(" - " + entry.Error())
+	}
+	if err != nil {
+		return err
+	}
+	if len(corrupt) == 0 {
+		fmt.Println("All cached entries are intact.")
+		return nil
+	}
+	fmt.Printf("Removed %d damaged cache entries.\n", len(corrupt))
+	return nil
+}
+
+func exportCache(cache pokecache.Cache, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	exported, err := pokecache.Export(cache, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d cached URLs to %s.\n", exported, path)
+	return nil
+}
+
+func importCache(cache pokecache.Cache, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	imported, err := pokecache.Import(cache, file)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Imported %d cached URLs from %s.\n", imported, path)
+	return nil
+}
+
+func printCacheStats(stats pokecache.Stats) {
+	lookups := stats.Hits + stats.Misses
+	hitRate := 0.0
+	if lookups > 0 {
+		hitRate = 100 * float64(stats.Hits) / float64(lookups)
+	}
+	fmt.Printf("Hits: %d\nStale hits: %d\nMisses: %d\nHit rate: %.1f%%\n", stats.Hits, stats.StaleHits, stats.Misses, hitRate)
+	fmt.Printf("Not found hits: %d\n", stats.NegativeHits)
+	fmt.Printf("Revalidations: %d\n", stats.Revalidations)
+	fmt.Printf("Evictions: %d\nExpirations: %d\n", stats.Evictions, stats.Expirations)
+	fmt.Printf("Corrupt entries: %d\n", stats.Corruptions)
+	fmt.Printf("Cache errors: %d\n", stats.Errors)
+	fmt.Printf("Entries: %d\nCompressed bytes: %d\nUncompressed bytes: %d\n", stats.Entries, stats.CompressedBytes, stats.UncompressedBytes)
+}
+
 func addCommand(command string) {
 	commandHistory = append(commandHistory, command)
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pokecache "github.com/kwekkwekpatu/gokedex/internal/pokecache"
+	pokedexapi "github.com/kwekkwekpatu/gokedex/internal/pokedexAPI"
+)
+
+func TestConfigReportsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	cache := pokecache.NewMemoryCache(time.Minute, pokecache.WithNegativeTTL(time.Minute))
+	defer cache.Close()
+	cfg := newConfig(cache, pokedexapi.WithBaseURL(server.URL), pokedexapi.WithRetry(pokedexapi.RetryPolicy{}))
+
+	ctx := context.Background()
+	ref := pokedexapi.NamedAPIResource[pokedexapi.Pokemon]{Name: "missingno", URL: cfg.client.ResourceURL("pokemon", "missingno")}
+	// The second round is answered by the cached 404.
+	for range 2 {
+		_, err := cfg.client.Pokemon(ctx, "missingno")
+		checkNotFound(t, "Pokemon", err)
+		_, err = ref.Resolve(ctx, cfg.client)
+		checkNotFound(t, "Resolve", err)
+		for _, result := range cfg.client.GetMany(ctx, []string{ref.URL}, 1) {
+			checkNotFound(t, "GetMany", result.Err)
+		}
+	}
+}
+
+func checkNotFound(t *testing.T, name string, err error) {
+	t.Helper()
+	var notFound *pokedexapi.NotFoundError
+	if !errors.As(err, &notFound) || !errors.Is(err, pokedexapi.ErrNotFound) {
+		t.Errorf("%s: expected a NotFoundError, got %v", name, err)
+	}
+}
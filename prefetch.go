@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pokedexapi "github.com/kwekkwekpatu/gokedex/internal/pokedexAPI"
+)
+
+// prefetchWorkers bounds the number of requests prefetch has in flight, so a
+// warm-up does not hammer PokeAPI.
+const prefetchWorkers = 8
+
+// prefetchPageSize is the page size used to walk the location-area listing.
+const prefetchPageSize = 100
+
+func commandPrefetch(cfg *config, dex *Pokedex, args ...string) error {
+	start := time.Now()
+	areas, err := prefetchLocationList(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	locations := pokedexapi.Batch(ctx, areas, prefetchWorkers,
+		func(ctx context.Context, url string) (pokedexapi.SpecificLocationResponse, error) {
+			body, err := fetch(ctx, cfg, url, locationTTL)
+			if err != nil {
+				return pokedexapi.SpecificLocationResponse{}, err
+			}
+			return pokedexapi.Decoder[pokedexapi.SpecificLocationResponse](cfg.client)(body)
+		}, printProgress("Locations", len(areas)))
+	failed := countFailed(locations)
+
+	seen := make(map[string]bool)
+	var pokemon []string
+	for _, location := range locations {
+		for _, encounter := range location.Value.PokemonEncounters {
+			url := pokedexapi.CanonicalURL(encounter.Pokemon.URL)
+			if !seen[url] {
+				seen[url] = true
+				pokemon = append(pokemon, url)
+			}
+		}
+	}
+
+	failed += countFailed(pokedexapi.Batch(ctx, pokemon, prefetchWorkers,
+		func(ctx context.Context, url string) ([]byte, error) {
+			return fetch(ctx, cfg, url, pokemonTTL)
+		}, printProgress("Pokemon", len(pokemon))))
+
+	fmt.Printf("Prefetched %d locations and %d pokemon in %s.\n", len(areas), len(pokemon), time.Since(start).Round(time.Second))
+	if failed > 0 {
+		fmt.Printf("%d requests failed, run prefetch again to retry them.\n", failed)
+	}
+	return nil
+}
+
+// prefetchLocationList walks the location-area pagination and returns the URL
+// of every area.
+func prefetchLocationList(cfg *config) ([]string, error) {
+	var areas []string
+	pager := pokedexapi.NewPager(cfg.client.ResourceURL("location-area"), prefetchPageSize,
+		func(ctx context.Context, url string) (pokedexapi.NamedAPIResourceList, error) {
+			body, err := fetch(ctx, cfg, url, locationListTTL)
+			if err != nil {
+				return pokedexapi.NamedAPIResourceList{}, err
+			}
+			return pokedexapi.Decoder[pokedexapi.NamedAPIResourceList](cfg.client)(body)
+		})
+	for {
+		page, err := pager.Next(context.Background())
+		if errors.Is(err, pokedexapi.ErrNoNextPage) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range page.Results {
+			areas = append(areas, result.URL)
+		}
+		fmt.Printf("\rLocation list: %d/%d", len(areas), page.Count)
+	}
+	fmt.Println()
+	return areas, nil
+}
+
+// printProgress returns a progress callback for pokedexapi.Batch that keeps
+// a line up to date and ends it once all total URLs are done.
+func printProgress(label string, total int) func(done int) {
+	return func(done int) {
+		fmt.Printf("\r%s: %d/%d", label, done, total)
+		if done == total {
+			fmt.Println()
+		}
+	}
+}
+
+// countFailed returns the number of results of a batch that are errors.
+func countFailed[T any](results []pokedexapi.Result[T]) int {
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// fetch returns the raw response for url through the byte cache, skipping the
+// decoded cache so bulk downloads do not push out hot entries.
+func fetch(ctx context.Context, cfg *config, url string, ttl time.Duration) ([]byte, error) {
+	key := cfg.aliases.Resolve(pokedexapi.CanonicalURL(url))
+	return cfg.cache.GetOrFetch(ctx, key, ttl, cfg.fetchFunc(key))
+}